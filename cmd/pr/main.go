@@ -1,21 +1,37 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"log/slog"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"pr-service/internal/config"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/idempotency"
+	"pr-service/internal/domain/policy"
 	"pr-service/internal/domain/pr"
+	"pr-service/internal/health"
 	"pr-service/internal/infrastructure/http/handlers"
 	mw "pr-service/internal/infrastructure/http/middleware"
 	"pr-service/internal/infrastructure/http/openapi"
+	"pr-service/internal/infrastructure/jwtauth"
+	"pr-service/internal/infrastructure/metrics"
 	"pr-service/internal/infrastructure/storage/postgres"
+	"pr-service/internal/infrastructure/tracing"
+	"pr-service/internal/lifecycle"
 	"pr-service/pkg/sl_logger/sl"
 	"pr-service/pkg/sl_logger/slogpretty"
+	"syscall"
+	"time"
+	// часовые пояса рабочего времени: в образе alpine нет tzdata
+	_ "time/tzdata"
 )
 
 const (
@@ -30,18 +46,23 @@ func main() {
 	log = log.With(slog.String("env", cfg.Env))
 
 	pgConfig := postgres.Config{
-		DSN: fmt.Sprintf("host=%s user=%s port=%s password=%s dbname=%s sslmode=%s",
-			cfg.DataBase.Host,
-			cfg.DataBase.User,
-			cfg.DataBase.Port,
-			cfg.DataBase.Password,
-			cfg.DataBase.Dbname,
-			cfg.DataBase.Sslmode,
-		),
+		DSN:            cfg.DataBase.DSN(),
 		Seed:           cfg.DataBase.Seed,
 		MigrationsPath: cfg.DataBase.MigrationsPath,
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Error("failed to init tracing", sl.Err(err))
+		os.Exit(1)
+	}
+
 	log.Info("CHECKING DB Conn,",
 		slog.String("Trying to connect with DSN", pgConfig.DSN),
 		slog.String("MiogrationPath", pgConfig.MigrationsPath))
@@ -52,19 +73,88 @@ func main() {
 		os.Exit(1)
 	}
 
-	_ = storage
-	service := pr.NewService(storage, log)
+	lc := lifecycle.New(log)
+	// Трассировка и БД регистрируются первыми, чтобы закрыться последними:
+	// span-ы последних запросов успевают выгрузиться.
+	lc.Append(lifecycle.Hook{
+		Name:   "tracing",
+		OnStop: shutdownTracing,
+	})
+	lc.Append(lifecycle.Hook{
+		Name:   "postgres",
+		OnStop: func(context.Context) error { return storage.Close() },
+	})
+
+	m := metrics.New()
+	sqlDB, err := storage.DB()
+	if err != nil {
+		log.Error("failed to get sql.DB", sl.Err(err))
+		os.Exit(1)
+	}
+	if err := m.RegisterDB(sqlDB, cfg.DataBase.Dbname); err != nil {
+		log.Error("failed to register db metrics", sl.Err(err))
+		os.Exit(1)
+	}
+
+	prCfg := pr.Config{ReviewSLA: cfg.Review.SLA}
+	if cfg.Review.Seed != 0 {
+		// фиксированное зерно делает назначения воспроизводимыми между запусками
+		prCfg.Rand = rand.New(rand.NewPCG(cfg.Review.Seed, 0))
+	}
+	service := pr.NewService(storage, log, m, prCfg)
+
+	authService, err := setupAuth(cfg.Auth, storage, log)
+	if err != nil {
+		log.Error("failed to init auth", sl.Err(err))
+		os.Exit(1)
+	}
 
 	r := chi.NewRouter()
-	r.Use(middleware.RequestID)
+	r.Use(mw.RequestID)
+	r.Use(mw.NewMWTracing())
 	r.Use(middleware.RedirectSlashes)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.URLFormat)
 	r.Use(mw.NewMWLogger(log))
-	api := &handlers.API{Log: log, Svc: service}
+	r.Use(mw.NewMWMetrics(m))
+	if cfg.Auth.Enabled {
+		r.Use(mw.NewMWAuth(authService, log, "/health/", "/metrics"))
+	} else {
+		log.Warn("authentication is disabled")
+		r.Use(mw.NewMWNoAuth())
+	}
+	r.Use(mw.NewMWIdempotency(storage, cfg.Idempotency.TTL, cfg.Idempotency.Lease, log,
+		"/pullRequest/create",
+		"/pullRequest/merge",
+		"/pullRequest/reassign",
+		"/pullRequest/bulkCreate",
+		"/team/add",
+		"/team/addMembers",
+		"/team/setMemberActive",
+		"/team/setLeadRules",
+		"/team/uploadCodeowners",
+		"/team/removeMembers",
+		"/team/rename",
+		"/team/setParent",
+		"/team/setMaxOpenReviews",
+		"/team/setSelectionMode",
+		"/users/setIsActive",
+		"/users/update",
+		"/users/setSkills",
+		"/users/setWorkingHours",
+		"/users/anonymize",
+	))
+	r.Handle("/metrics", m.Handler())
+	api := &handlers.API{
+		Log:    log,
+		Svc:    service,
+		Auth:   authService,
+		Policy: policy.New(storage),
+		Health: readinessChecks(storage, lc),
+	}
 
 	openapi.HandlerFromMux(api, r)
-	
+
 	srv := &http.Server{
 		Addr:         cfg.Address,
 		Handler:      r,
@@ -73,15 +163,109 @@ func main() {
 		IdleTimeout:  cfg.IdleTimeout,
 	}
 
+	lc.Go("idempotency-cleanup", func(ctx context.Context) error {
+		return idempotency.Cleanup(ctx, storage, cfg.Idempotency.CleanupInterval, log)
+	})
+	lc.Append(httpServerHook(srv, lc, log))
+
 	log.Info("starting HTTP server",
 		slog.String("address", cfg.Address),
 		slog.Duration("read_timeout", cfg.HTTPServer.Timeout),
 		slog.Duration("write_timeout", cfg.HTTPServer.Timeout),
 		slog.Duration("idle_timeout", cfg.IdleTimeout),
+		slog.Duration("shutdown_timeout", cfg.ShutdownTimeout),
 	)
 
-	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Error("failed to start server", sl.Err(err))
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := lc.Run(ctx, cfg.ShutdownTimeout); err != nil {
+		log.Error("service stopped with error", sl.Err(err))
+		os.Exit(1)
+	}
+	log.Info("service stopped")
+}
+
+func setupAuth(cfg config.Auth, storage *postgres.PostgresStorage, log *slog.Logger) (*auth.Service, error) {
+	var tokens auth.TokenVerifier
+	if cfg.JWT.HMACSecret != "" || cfg.JWT.JWKSPath != "" {
+		verifier, err := jwtauth.New(jwtauth.Config{
+			HMACSecret:   cfg.JWT.HMACSecret,
+			JWKSPath:     cfg.JWT.JWKSPath,
+			Issuer:       cfg.JWT.Issuer,
+			Audience:     cfg.JWT.Audience,
+			DefaultOrgID: cfg.JWT.DefaultOrgID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tokens = verifier
+	}
+
+	authService := auth.NewService(storage, tokens, log)
+	if err := authService.EnsureBootstrapKey(context.Background(), cfg.BootstrapKey); err != nil {
+		return nil, err
+	}
+	return authService, nil
+}
+
+func readinessChecks(storage *postgres.PostgresStorage, lc *lifecycle.Lifecycle) *health.Registry {
+	const checkTimeout = 2 * time.Second
+
+	reg := health.NewRegistry(checkTimeout)
+	reg.Register("postgres", func(ctx context.Context) (map[string]any, error) {
+		return nil, storage.Ping(ctx)
+	})
+	reg.Register("migrations", func(ctx context.Context) (map[string]any, error) {
+		current, expected, err := storage.MigrationVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		details := map[string]any{"current": current, "expected": expected}
+		if current != expected {
+			return details, fmt.Errorf("schema version %d, expected %d", current, expected)
+		}
+		return details, nil
+	})
+	reg.Register("workers", func(context.Context) (map[string]any, error) {
+		details := make(map[string]any)
+		var failed []string
+		for name, state := range lc.Workers() {
+			details[name] = state
+			if state != lifecycle.WorkerRunning {
+				failed = append(failed, name)
+			}
+		}
+		if lc.Stopping() {
+			return details, errors.New("shutting down")
+		}
+		if len(failed) > 0 {
+			return details, fmt.Errorf("workers not running: %v", failed)
+		}
+		return details, nil
+	})
+	return reg
+}
+
+// httpServerHook занимает адрес синхронно, чтобы ошибка адреса прерывала
+// запуск, обслуживает запросы в фоне и при остановке дожидается текущих.
+func httpServerHook(srv *http.Server, lc *lifecycle.Lifecycle, log *slog.Logger) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: "http",
+		OnStart: func(context.Context) error {
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Error("failed to serve", sl.Err(err))
+					lc.Fail(err)
+				}
+			}()
+			return nil
+		},
+		OnStop: srv.Shutdown,
 	}
 }
 
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"pr-service/internal/domain/pr"
+	"pr-service/internal/infrastructure/storage/postgres"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-playground/validator"
+	"gopkg.in/yaml.v3"
+)
+
+// teamsFile — формат `prctl team import`; поля как в теле /team/add.
+type teamsFile struct {
+	Teams []teamYAML `yaml:"teams" validate:"required,min=1,dive"`
+}
+
+type teamYAML struct {
+	TeamName           string       `yaml:"team_name" validate:"required"`
+	ParentTeam         string       `yaml:"parent_team"`
+	SelectionMode      string       `yaml:"selection_mode" validate:"omitempty,oneof=random spread"`
+	MaxOpenReviews     int          `yaml:"max_open_reviews" validate:"min=0"`
+	PreferWorkingHours bool         `yaml:"prefer_working_hours"`
+	Members            []memberYAML `yaml:"members" validate:"dive"`
+}
+
+type memberYAML struct {
+	UserId   string `yaml:"user_id" validate:"required"`
+	Username string `yaml:"username" validate:"required"`
+	// IsActive по умолчанию true: в файле обычно перечисляют действующих участников
+	IsActive *bool  `yaml:"is_active"`
+	Role     string `yaml:"role" validate:"omitempty,oneof=member lead"`
+}
+
+// teamImport создаёт команды из файла по порядку, поэтому родительская
+// команда должна идти раньше подкоманд. В существующие команды добавляются
+// участники, настройки команды при этом не меняются. Ошибка по одной команде
+// не останавливает импорт остальных.
+func (c *cli) teamImport(ctx context.Context, svc pr.Service, path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var file teamsFile
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := validator.New().Struct(file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	failed := 0
+	for _, t := range file.Teams {
+		team := t.toModel()
+		result := "created"
+		_, err := svc.TeamAdd(ctx, team)
+		if errors.Is(err, postgres.ErrTeamExists) && len(team.Members) > 0 {
+			result = "members added"
+			_, err = svc.TeamAddMembers(ctx, pr.TeamAddMembers{
+				TeamName:    team.TeamName,
+				Members:     team.Members,
+				OpenReviews: pr.OpenReviewsKeep,
+			})
+		}
+		if err != nil {
+			failed++
+			fmt.Fprintf(c.out, "%s\tfailed: %v\n", team.TeamName, err)
+			continue
+		}
+		fmt.Fprintf(c.out, "%s\t%s (%d members)\n", team.TeamName, result, len(team.Members))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d teams not imported", failed, len(file.Teams))
+	}
+	return nil
+}
+
+func (t teamYAML) toModel() pr.Team {
+	team := pr.Team{
+		TeamName:           t.TeamName,
+		ParentTeam:         t.ParentTeam,
+		SelectionMode:      pr.SelectionMode(t.SelectionMode),
+		MaxOpenReviews:     t.MaxOpenReviews,
+		PreferWorkingHours: t.PreferWorkingHours,
+		Members:            make([]pr.TeamMember, 0, len(t.Members)),
+	}
+	for _, m := range t.Members {
+		member := pr.TeamMember{
+			UserId:   m.UserId,
+			Username: m.Username,
+			IsActive: m.IsActive == nil || *m.IsActive,
+			Role:     pr.MembershipRole(m.Role),
+		}
+		team.Members = append(team.Members, member)
+	}
+	return team
+}
+
+// userDeactivate выключает пользователя и заменяет его во всех открытых
+// ревью. Пользователь деактивируется первым, чтобы не стать заменой самому
+// себе в соседнем PR. PR, для которых замены нет, остаются за ним и
+// перечисляются в выводе.
+func (c *cli) userDeactivate(ctx context.Context, svc pr.Service, userID string) error {
+	if err := svc.UsersSetIsActive(ctx, pr.UsersSetIsActive{UserId: userID, IsActive: false}); err != nil {
+		return err
+	}
+	reviews, err := svc.GetUsersReview(ctx, pr.GetReviewParams{UserId: userID})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PULL REQUEST\tREVIEWERS\t")
+	failed := 0
+	for _, p := range reviews {
+		if p.Status != "OPEN" {
+			continue
+		}
+		updated, err := svc.PullRequestReassign(ctx, pr.PostPullRequestReassign{
+			PullRequestId: p.PullRequestId,
+			OldUserId:     userID,
+		})
+		if err != nil {
+			failed++
+			fmt.Fprintf(w, "%s\tfailed: %v\t\n", p.PullRequestId, err)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t\n", p.PullRequestId, strings.Join(updated.AssignedReviewers, ", "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("user %s deactivated, %d reviews not reassigned", userID, failed)
+	}
+	return nil
+}
+
+func (c *cli) prReassign(ctx context.Context, svc pr.Service, prID, oldUserID string) error {
+	updated, err := svc.PullRequestReassign(ctx, pr.PostPullRequestReassign{
+		PullRequestId: prID,
+		OldUserId:     oldUserID,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "%s\treviewers: %s\n", prID, strings.Join(updated.AssignedReviewers, ", "))
+	return nil
+}
+
+func (c *cli) stats(ctx context.Context, svc pr.Service, teamName string) error {
+	stats, err := svc.TeamStats(ctx, teamName)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "TEAM\tMEMBERS\tACTIVE\tOPEN PRS\tMERGED PRS\tOPEN REVIEWS\t")
+	row := func(name string, r pr.TeamStatsRow) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t\n",
+			name, r.Members, r.ActiveMembers, r.OpenPullRequests, r.MergedPullRequests, r.OpenReviews)
+	}
+	for _, r := range stats.Teams {
+		row(r.TeamName, r)
+	}
+	row("total", stats.Total)
+	return w.Flush()
+}
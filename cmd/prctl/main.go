@@ -0,0 +1,179 @@
+// Command prctl — служебные операции над базой сервиса без psql и HTTP API:
+// миграции, сиды, импорт команд, вывод пользователя из ревью, переназначение
+// и показатели команд. Конфигурация та же, что у сервиса (CONFIG_PATH).
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"pr-service/internal/config"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	"pr-service/internal/infrastructure/storage/postgres"
+	"syscall"
+	// часовые пояса рабочего времени: в образе alpine нет tzdata
+	_ "time/tzdata"
+)
+
+const usage = `Использование: prctl [-config путь] [-org организация] [-v] <команда>
+
+Команды:
+  migrate up|down|status           применить, откатить последнюю миграцию или показать их состояние
+  seed                             заполнить базу тестовыми командами и пользователями
+  team import <файл.yaml>          создать команды из YAML или добавить участников в существующие
+  user deactivate <user_id>        деактивировать пользователя и переназначить его открытые ревью
+  pr reassign <pr_id> <user_id>    заменить ревьювера PR
+  stats <team_name>                показатели команды и её подкоманд
+`
+
+// errUsage — команда вызвана с неверными аргументами
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+	flags := flag.NewFlagSet("prctl", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	configPath := flags.String("config", "", "путь к конфигу сервиса (по умолчанию CONFIG_PATH)")
+	org := flags.String("org", auth.DefaultOrgID, "организация, в которой выполняется команда")
+	verbose := flags.Bool("v", false, "подробный лог сервиса в stderr")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *configPath != "" {
+		os.Setenv("CONFIG_PATH", *configPath)
+	}
+
+	cfg := config.MustLoad()
+	level := slog.LevelWarn
+	if *verbose {
+		level = slog.LevelDebug
+	}
+	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	// служебные команды выполняются от имени администратора организации
+	ctx = auth.WithPrincipal(ctx, auth.Principal{
+		Subject: "prctl",
+		Role:    auth.RoleAdmin,
+		OrgID:   *org,
+		Method:  auth.MethodNone,
+	})
+
+	c := &cli{
+		out: os.Stdout,
+		log: log,
+		pgConfig: postgres.Config{
+			DSN:            cfg.DataBase.DSN(),
+			MigrationsPath: cfg.DataBase.MigrationsPath,
+			SkipMigrations: true,
+		},
+		prConfig: pr.Config{ReviewSLA: cfg.Review.SLA},
+	}
+	err := c.run(ctx, flags.Args())
+	switch {
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(os.Stderr, err)
+		flags.Usage()
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "prctl:", err)
+		os.Exit(1)
+	}
+}
+
+type cli struct {
+	out      io.Writer
+	log      *slog.Logger
+	pgConfig postgres.Config
+	prConfig pr.Config
+}
+
+func (c *cli) run(ctx context.Context, args []string) error {
+	switch {
+	case len(args) == 2 && args[0] == "migrate":
+		switch args[1] {
+		case "up", "down", "status":
+			return postgres.Migrate(ctx, c.pgConfig, args[1])
+		}
+	case len(args) == 1 && args[0] == "seed":
+		return c.seed(ctx)
+	case len(args) == 3 && args[0] == "team" && args[1] == "import":
+		return c.withService(ctx, func(svc pr.Service) error {
+			return c.teamImport(ctx, svc, args[2])
+		})
+	case len(args) == 3 && args[0] == "user" && args[1] == "deactivate":
+		return c.withService(ctx, func(svc pr.Service) error {
+			return c.userDeactivate(ctx, svc, args[2])
+		})
+	case len(args) == 4 && args[0] == "pr" && args[1] == "reassign":
+		return c.withService(ctx, func(svc pr.Service) error {
+			return c.prReassign(ctx, svc, args[2], args[3])
+		})
+	case len(args) == 2 && args[0] == "stats":
+		return c.withService(ctx, func(svc pr.Service) error {
+			return c.stats(ctx, svc, args[1])
+		})
+	}
+	return fmt.Errorf("%w: %v", errUsage, args)
+}
+
+// withStorage подключается к базе без миграций: схему меняет только
+// migrate up, чтобы служебная команда не обновила её незаметно. Организация
+// -org должна существовать: иначе команда ничего не найдёт или создаст
+// данные организации, которой нет.
+func (c *cli) withStorage(ctx context.Context, fn func(*postgres.PostgresStorage) error) error {
+	storage, err := postgres.New(c.pgConfig, c.log)
+	if err != nil {
+		return err
+	}
+	defer storage.Close()
+
+	current, expected, err := storage.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if current != expected {
+		return fmt.Errorf("schema version %d, expected %d: run prctl migrate up", current, expected)
+	}
+	orgID := auth.OrgID(ctx)
+	exists, err := storage.OrganizationExists(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", auth.ErrOrgNotFound, orgID)
+	}
+	return fn(storage)
+}
+
+func (c *cli) withService(ctx context.Context, fn func(pr.Service) error) error {
+	return c.withStorage(ctx, func(storage *postgres.PostgresStorage) error {
+		return fn(pr.NewService(storage, c.log, nil, c.prConfig))
+	})
+}
+
+// seed добавляет тестовые данные в организацию -org, как database.seed
+// при старте сервиса — в организацию по умолчанию.
+func (c *cli) seed(ctx context.Context) error {
+	return c.withStorage(ctx, func(storage *postgres.PostgresStorage) error {
+		sqlDB, err := storage.DB()
+		if err != nil {
+			return err
+		}
+		if err := postgres.SeedUsersWithTeams(ctx, sqlDB); err != nil {
+			return err
+		}
+		fmt.Fprintln(c.out, "seeded")
+		return nil
+	})
+}
@@ -2,6 +2,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -10,15 +11,22 @@ import (
 )
 
 type Config struct {
-	Env        string `yaml:"env" env-defaut:"dev"`
-	HTTPServer `yaml:"http_server"`
-	DataBase   `yaml:"database"`
+	Env         string `yaml:"env" env-defaut:"dev"`
+	HTTPServer  `yaml:"http_server"`
+	DataBase    `yaml:"database"`
+	Tracing     Tracing     `yaml:"tracing"`
+	Auth        Auth        `yaml:"auth"`
+	Idempotency Idempotency `yaml:"idempotency"`
+	Review      Review      `yaml:"review"`
 }
 
 type HTTPServer struct {
 	Address     string        `yaml:"address" env-defaut:"0.0.0.0:8080"`
 	Timeout     time.Duration `yaml:"timeout" env-default:"5s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	// ShutdownTimeout — сколько по SIGTERM/SIGINT ждать завершения текущих
+	// запросов и фоновых воркеров.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"15s"`
 }
 
 type DataBase struct {
@@ -32,6 +40,53 @@ type DataBase struct {
 	MigrationsPath string `yaml:"migration_path" env-default:"internal/infrastructure/storage/postgres/migrations"`
 }
 
+// DSN — строка подключения к Postgres для lib/pq.
+func (d DataBase) DSN() string {
+	return fmt.Sprintf("host=%s user=%s port=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.User, d.Port, d.Password, d.Dbname, d.Sslmode)
+}
+
+type Tracing struct {
+	// Exporter — none, stdout (локальный запуск) или otlp
+	Exporter    string  `yaml:"exporter" env-default:"none"`
+	Endpoint    string  `yaml:"endpoint" env-default:"localhost:4318"`
+	Insecure    bool    `yaml:"insecure" env-default:"true"`
+	ServiceName string  `yaml:"service_name" env-default:"pr-service"`
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
+}
+
+type Auth struct {
+	Enabled bool `yaml:"enabled" env-default:"true"`
+	// BootstrapKey — ключ администратора, регистрируемый при старте (хранится
+	// хэш). Только из окружения: в конфиге ключ попал бы в образ и репозиторий
+	BootstrapKey string `yaml:"-" env:"AUTH_BOOTSTRAP_KEY"`
+	JWT          JWT    `yaml:"jwt"`
+}
+
+type JWT struct {
+	HMACSecret string `yaml:"hmac_secret" env:"AUTH_JWT_HMAC_SECRET"`
+	JWKSPath   string `yaml:"jwks_path" env:"AUTH_JWT_JWKS_PATH"`
+	Issuer     string `yaml:"issuer"`
+	Audience   string `yaml:"audience"`
+	// DefaultOrgID — организация токенов без org_id; пусто — такие токены отклоняются
+	DefaultOrgID string `yaml:"default_org_id"`
+}
+
+type Idempotency struct {
+	// TTL — сколько хранится ответ на запрос с Idempotency-Key
+	TTL time.Duration `yaml:"ttl" env-default:"24h"`
+	// Lease — сколько незавершённый запрос держит ключ; больше http_server.timeout
+	Lease           time.Duration `yaml:"lease" env-default:"5m"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env-default:"10m"`
+}
+
+type Review struct {
+	// SLA — срок ревью в рабочем времени ревьювера; 0 — сроки не считаются
+	SLA time.Duration `yaml:"sla" env-default:"8h"`
+	// Seed — зерно источника подбора ревьюверов; 0 — случайное при старте
+	Seed uint64 `yaml:"seed" env:"REVIEW_SEED"`
+}
+
 func MustLoad() *Config {
 	configPath := os.Getenv("CONFIG_PATH")
 	if configPath == "" {
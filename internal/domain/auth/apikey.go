@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// keyPrefix отличает ключи сервиса от прочих секретов (удобно для сканеров утечек).
+const keyPrefix = "prk_"
+
+// APIKey — статический ключ. Сам ключ не хранится, только его SHA-256.
+type APIKey struct {
+	KeyID     string
+	Name      string
+	Role      Role
+	Subject   string
+	TeamName  string
+	OrgID     string
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+func (k APIKey) Principal() Principal {
+	return Principal{
+		Subject:  k.Subject,
+		Role:     k.Role,
+		TeamName: k.TeamName,
+		OrgID:    k.OrgID,
+		Method:   MethodAPIKey,
+		KeyID:    k.KeyID,
+	}
+}
+
+// KeyStore хранит API-ключи.
+type KeyStore interface {
+	APIKeyCreate(ctx context.Context, key APIKey, hash string) error
+	// APIKeyReplace отзывает действующие ключи с тем же Subject в организации
+	// ключа и создаёт key; возвращает число отозванных
+	APIKeyReplace(ctx context.Context, key APIKey, hash string) (revoked int64, err error)
+	APIKeyByHash(ctx context.Context, hash string) (APIKey, error)
+	APIKeyList(ctx context.Context) ([]APIKey, error)
+	APIKeyRevoke(ctx context.Context, id string) error
+}
+
+// HashKey возвращает hex(SHA-256) ключа. Ключи высокоэнтропийные, поэтому
+// медленный KDF не нужен, а поиск по хэшу остаётся индексным.
+func HashKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + hex.EncodeToString(b), nil
+}
+
+func generateKeyID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
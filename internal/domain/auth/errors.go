@@ -0,0 +1,13 @@
+package auth
+
+import "errors"
+
+var (
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrInvalidKey      = errors.New("invalid api key")
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrKeyNotFound     = errors.New("api key not found")
+	ErrInvalidRole     = errors.New("invalid role")
+	ErrOrgNotFound     = errors.New("organization not found")
+	ErrOrgExists       = errors.New("organization already exists")
+)
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Organization — арендатор сервиса. Команды, пользователи и PR изолированы
+// в пределах организации.
+type Organization struct {
+	OrgID     string
+	Name      string
+	CreatedAt time.Time
+}
+
+type OrgStore interface {
+	OrganizationCreate(ctx context.Context, org Organization) error
+	OrganizationList(ctx context.Context) ([]Organization, error)
+	OrganizationExists(ctx context.Context, orgID string) (bool, error)
+}
@@ -0,0 +1,73 @@
+// Package auth описывает аутентифицированных principal-ов и управление API-ключами.
+package auth
+
+import "context"
+
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"
+	RoleTeamLead Role = "team_lead"
+	RoleMember   Role = "member"
+	RoleBot      Role = "bot"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleTeamLead, RoleMember, RoleBot:
+		return true
+	}
+	return false
+}
+
+// Method — способ, которым был аутентифицирован запрос.
+type Method string
+
+const (
+	MethodAPIKey Method = "api_key"
+	MethodJWT    Method = "jwt"
+	// MethodNone — аутентификация отключена в конфигурации
+	MethodNone Method = "none"
+)
+
+// DefaultOrgID — организация, в которую перенесены данные до появления
+// мультитенантности. Её администраторы управляют остальными организациями.
+const DefaultOrgID = "default"
+
+// Principal — аутентифицированный субъект запроса.
+type Principal struct {
+	// Subject — user_id пользователя или имя бота
+	Subject  string
+	Role     Role
+	TeamName string
+	// OrgID — организация, в пределах которой действует principal
+	OrgID  string
+	Method Method
+	// KeyID заполнен для аутентификации по API-ключу
+	KeyID string
+}
+
+type ctxKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(ctxKey{}).(Principal)
+	return p, ok
+}
+
+// OrgID возвращает организацию principal-а запроса. Вне запроса (фоновые
+// задачи, сиды) используется организация по умолчанию.
+func OrgID(ctx context.Context) string {
+	if p, ok := PrincipalFromContext(ctx); ok && p.OrgID != "" {
+		return p.OrgID
+	}
+	return DefaultOrgID
+}
+
+// IsPlatformAdmin — администратор организации по умолчанию.
+func (p Principal) IsPlatformAdmin() bool {
+	return p.Role == RoleAdmin && p.OrgID == DefaultOrgID
+}
@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// TokenVerifier проверяет bearer-токены локально (без обращения к внешним сервисам).
+type TokenVerifier interface {
+	Verify(token string) (Principal, error)
+}
+
+// Store — хранилище ключей и организаций.
+type Store interface {
+	KeyStore
+	OrgStore
+}
+
+type Service struct {
+	store  Store
+	tokens TokenVerifier
+	log    *slog.Logger
+}
+
+// NewService создаёт сервис аутентификации. tokens может быть nil, тогда
+// bearer-токены не принимаются.
+func NewService(store Store, tokens TokenVerifier, log *slog.Logger) *Service {
+	return &Service{store: store, tokens: tokens, log: log}
+}
+
+type NewAPIKey struct {
+	Name     string
+	Role     Role
+	Subject  string
+	TeamName string
+	// OrgID — организация ключа; пустая означает организацию создателя
+	OrgID string
+}
+
+func (s *Service) AuthenticateAPIKey(ctx context.Context, raw string) (Principal, error) {
+	const op = "auth.AuthenticateAPIKey"
+
+	key, err := s.store.APIKeyByHash(ctx, HashKey(raw))
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return Principal{}, ErrInvalidKey
+		}
+		return Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if key.RevokedAt != nil {
+		return Principal{}, ErrInvalidKey
+	}
+	return key.Principal(), nil
+}
+
+func (s *Service) AuthenticateBearer(_ context.Context, token string) (Principal, error) {
+	if s.tokens == nil {
+		return Principal{}, ErrInvalidToken
+	}
+	return s.tokens.Verify(token)
+}
+
+// CreateAPIKey создаёт ключ и возвращает его в открытом виде. Повторно
+// получить открытый ключ нельзя.
+func (s *Service) CreateAPIKey(ctx context.Context, req NewAPIKey) (APIKey, string, error) {
+	const op = "auth.CreateAPIKey"
+
+	if req.OrgID != "" && req.OrgID != OrgID(ctx) {
+		exists, err := s.store.OrganizationExists(ctx, req.OrgID)
+		if err != nil {
+			return APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+		if !exists {
+			return APIKey{}, "", ErrOrgNotFound
+		}
+	}
+
+	raw, err := generateKey()
+	if err != nil {
+		return APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+	key, err := s.storeKey(ctx, req, raw)
+	if err != nil {
+		return APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Info("api key created",
+		slog.String("key_id", key.KeyID),
+		slog.String("name", key.Name),
+		slog.String("role", string(key.Role)),
+		slog.String("org_id", key.OrgID),
+	)
+	return key, raw, nil
+}
+
+// bootstrapSubject — Subject ключа администратора из AUTH_BOOTSTRAP_KEY
+const bootstrapSubject = "bootstrap"
+
+// EnsureBootstrapKey регистрирует ключ администратора из AUTH_BOOTSTRAP_KEY,
+// если его ещё нет. Нужен, чтобы выпустить первые ключи через API. Новый
+// ключ заменяет прежние bootstrap-ключи: они отзываются, чтобы смена
+// переменной окружения действительно отзывала старый ключ.
+func (s *Service) EnsureBootstrapKey(ctx context.Context, raw string) error {
+	const op = "auth.EnsureBootstrapKey"
+
+	if raw == "" {
+		return nil
+	}
+	_, err := s.store.APIKeyByHash(ctx, HashKey(raw))
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrKeyNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := newKey(ctx, NewAPIKey{Name: "bootstrap", Role: RoleAdmin, Subject: bootstrapSubject, OrgID: DefaultOrgID})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	revoked, err := s.store.APIKeyReplace(ctx, key, HashKey(raw))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.log.Info("bootstrap admin api key registered", slog.Int64("revoked", revoked))
+	return nil
+}
+
+func (s *Service) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	const op = "auth.ListAPIKeys"
+
+	keys, err := s.store.APIKeyList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return keys, nil
+}
+
+func (s *Service) RevokeAPIKey(ctx context.Context, id string) error {
+	const op = "auth.RevokeAPIKey"
+
+	if err := s.store.APIKeyRevoke(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.log.Info("api key revoked", slog.String("key_id", id))
+	return nil
+}
+
+func (s *Service) storeKey(ctx context.Context, req NewAPIKey, raw string) (APIKey, error) {
+	key, err := newKey(ctx, req)
+	if err != nil {
+		return APIKey{}, err
+	}
+	if err := s.store.APIKeyCreate(ctx, key, HashKey(raw)); err != nil {
+		return APIKey{}, err
+	}
+	return key, nil
+}
+
+func newKey(ctx context.Context, req NewAPIKey) (APIKey, error) {
+	if !req.Role.Valid() {
+		return APIKey{}, ErrInvalidRole
+	}
+	id, err := generateKeyID()
+	if err != nil {
+		return APIKey{}, err
+	}
+	if req.OrgID == "" {
+		req.OrgID = OrgID(ctx)
+	}
+
+	key := APIKey{
+		KeyID:     id,
+		Name:      req.Name,
+		Role:      req.Role,
+		Subject:   req.Subject,
+		TeamName:  req.TeamName,
+		OrgID:     req.OrgID,
+		CreatedAt: time.Now(),
+	}
+	return key, nil
+}
+
+func (s *Service) CreateOrganization(ctx context.Context, org Organization) (Organization, error) {
+	const op = "auth.CreateOrganization"
+
+	org.CreatedAt = time.Now()
+	if err := s.store.OrganizationCreate(ctx, org); err != nil {
+		return Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+	s.log.Info("organization created", slog.String("org_id", org.OrgID))
+	return org, nil
+}
+
+func (s *Service) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	const op = "auth.ListOrganizations"
+
+	orgs, err := s.store.OrganizationList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return orgs, nil
+}
@@ -0,0 +1,104 @@
+// Package idempotency хранит ответы на мутирующие запросы с заголовком
+// Idempotency-Key, чтобы повтор запроса не выполнял операцию второй раз.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"pr-service/pkg/sl_logger/sl"
+	"time"
+)
+
+var (
+	// ErrKeyReused — ключ уже использован для запроса с другим телом или эндпоинтом.
+	ErrKeyReused = errors.New("idempotency key reused with different request")
+	// ErrInProgress — запрос с этим ключом ещё выполняется.
+	ErrInProgress = errors.New("request with this idempotency key is in progress")
+)
+
+// Record — сохранённый запрос и, после завершения, его ответ.
+type Record struct {
+	OrgID string
+	// Subject — principal запроса; ключи разных principal-ов не пересекаются
+	Subject     string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	// ETag — версия ресурса из ответа, чтобы повтор отдал тот же If-Match
+	ETag      string
+	Body      []byte
+	Completed bool
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// LockedUntil — до какого момента незавершённый запрос держит ключ. Если
+	// процесс упал, не освободив ключ, повтор после этого момента занимает его.
+	LockedUntil time.Time
+}
+
+type Store interface {
+	// IdempotencyReserve атомарно занимает ключ. Если ключ занят, не истёк и
+	// не брошен (аренда незавершённого запроса ещё идёт), возвращает
+	// существующую запись и reserved = false.
+	IdempotencyReserve(ctx context.Context, rec Record) (existing Record, reserved bool, err error)
+	// IdempotencyComplete сохраняет ответ, если ключ всё ещё занят этой
+	// резервацией (rec.CreatedAt), а не перехвачен после аренды.
+	IdempotencyComplete(ctx context.Context, rec Record) error
+	// IdempotencyRelease освобождает незавершённый ключ этой резервации, чтобы
+	// запрос можно было повторить.
+	IdempotencyRelease(ctx context.Context, rec Record) error
+	IdempotencyDeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// HashRequest — отпечаток запроса: метод, путь и тело.
+func HashRequest(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Check сопоставляет повторный запрос с сохранённой записью: совпадающий
+// завершённый запрос можно воспроизвести, остальные случаи — ошибки.
+func Check(existing Record, requestHash string) error {
+	if existing.RequestHash != requestHash {
+		return ErrKeyReused
+	}
+	if !existing.Completed {
+		return ErrInProgress
+	}
+	return nil
+}
+
+// Cleanup периодически удаляет истёкшие ключи до отмены ctx.
+func Cleanup(ctx context.Context, store Store, interval time.Duration, log *slog.Logger) error {
+	log = log.With(slog.String("component", "idempotency/cleanup"))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			n, err := store.IdempotencyDeleteExpired(ctx, now)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Error("failed to delete expired keys", sl.Err(err))
+				continue
+			}
+			if n > 0 {
+				log.Debug("expired keys deleted", slog.Int64("count", n))
+			}
+		}
+	}
+}
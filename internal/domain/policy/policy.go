@@ -0,0 +1,234 @@
+// Package policy решает, может ли аутентифицированный principal выполнить операцию.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	"slices"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// Store — данные, необходимые для проверки прав.
+type Store interface {
+	GetAuthorTeam(ctx context.Context, userID string) (string, error)
+	UserTeams(ctx context.Context, userID string) ([]string, error)
+	PullRequestGet(ctx context.Context, id string) (pr.PullRequest, error)
+}
+
+// Policy реализует правила:
+//   - admin может всё;
+//   - team_lead управляет своей командой (team/add, состав, переименование,
+//     setIsActive её участников), но не может забирать участников чужих команд;
+//   - удаление команды, иерархия команд и анонимизация пользователей — только admin;
+//   - имя пользователя меняет он сам, его лид или admin; команду — лид
+//     (только в пределах своей команды) или admin;
+//   - PR переназначает и мержит автор, назначенный ревьювер или лид команды PR;
+//   - пакетный импорт PR — только admin;
+//   - bot действует с PR от имени автоматизации: любой PR, если у ключа нет команды,
+//     иначе только PR своей команды;
+//   - управление API-ключами — только admin своей организации;
+//   - организации и ключи чужих организаций — только администратор платформы
+//     (admin организации по умолчанию).
+type Policy struct {
+	store Store
+}
+
+func New(store Store) *Policy {
+	return &Policy{store: store}
+}
+
+func (p *Policy) CanManageAPIKeys(principal auth.Principal) error {
+	if principal.Role == auth.RoleAdmin {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanManageOrgAPIKeys — выпуск ключа для указанной организации.
+func (p *Policy) CanManageOrgAPIKeys(principal auth.Principal, orgID string) error {
+	if orgID == principal.OrgID {
+		return p.CanManageAPIKeys(principal)
+	}
+	return p.CanManageOrganizations(principal)
+}
+
+func (p *Policy) CanManageOrganizations(principal auth.Principal) error {
+	if principal.IsPlatformAdmin() {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanManageTeam — создание команды и изменение её состава.
+func (p *Policy) CanManageTeam(_ context.Context, principal auth.Principal, teamName string) error {
+	if principal.Role == auth.RoleAdmin {
+		return nil
+	}
+	if p.isLeadOf(principal, teamName) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanAddMembers — добавление пользователей в команду. Пользователя из другой
+// команды может перевести только admin или лид обеих команд.
+func (p *Policy) CanAddMembers(ctx context.Context, principal auth.Principal, teamName string, userIDs []string) error {
+	const op = "policy.CanAddMembers"
+
+	if principal.Role == auth.RoleAdmin {
+		return nil
+	}
+	if !p.isLeadOf(principal, teamName) {
+		return ErrForbidden
+	}
+
+	for _, id := range userIDs {
+		teams, err := p.store.UserTeams(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		for _, current := range teams {
+			if current != teamName {
+				return ErrForbidden
+			}
+		}
+	}
+	return nil
+}
+
+// CanManageHierarchy — перенос команд в иерархии и создание подкоманд.
+func (p *Policy) CanManageHierarchy(principal auth.Principal) error {
+	if principal.Role == auth.RoleAdmin {
+		return nil
+	}
+	return ErrForbidden
+}
+
+func (p *Policy) CanDeleteTeam(principal auth.Principal) error {
+	if principal.Role == auth.RoleAdmin {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanImportPullRequests — пакетное создание PR, в том числе с прошлыми датами.
+func (p *Policy) CanImportPullRequests(principal auth.Principal) error {
+	if principal.Role == auth.RoleAdmin {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanSetUserActive — изменение флага активности пользователя.
+func (p *Policy) CanSetUserActive(ctx context.Context, principal auth.Principal, userID string) error {
+	const op = "policy.CanSetUserActive"
+
+	if principal.Role == auth.RoleAdmin {
+		return nil
+	}
+	if principal.Role != auth.RoleTeamLead {
+		return ErrForbidden
+	}
+
+	teams, err := p.store.UserTeams(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if slices.ContainsFunc(teams, func(team string) bool { return p.isLeadOf(principal, team) }) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanUpdateUser — изменение имени, команды, грейда и лимита ревью пользователя.
+// teamName == nil — команда не меняется, пустая строка — исключение из команды.
+// restricted — меняются грейд или лимит открытых ревью: их пользователь сам себе
+// не задаёт, от грейда зависит, добавляются ли к его PR лиды, а от лимита —
+// сколько ревью ему достаётся.
+func (p *Policy) CanUpdateUser(ctx context.Context, principal auth.Principal, userID string, teamName *string, restricted bool) error {
+	const op = "policy.CanUpdateUser"
+
+	if principal.Role == auth.RoleAdmin {
+		return nil
+	}
+	if teamName == nil && !restricted && principal.Role != auth.RoleBot && principal.Subject == userID {
+		return nil
+	}
+	if principal.Role != auth.RoleTeamLead {
+		return ErrForbidden
+	}
+
+	current, err := p.store.GetAuthorTeam(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !p.isLeadOf(principal, current) {
+		return ErrForbidden
+	}
+	if teamName != nil && *teamName != "" && !p.isLeadOf(principal, *teamName) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (p *Policy) CanAnonymizeUser(principal auth.Principal) error {
+	if principal.Role == auth.RoleAdmin {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanModifyPullRequest — merge и переназначение ревьюверов.
+func (p *Policy) CanModifyPullRequest(ctx context.Context, principal auth.Principal, prID string) error {
+	const op = "policy.CanModifyPullRequest"
+
+	if principal.Role == auth.RoleAdmin {
+		return nil
+	}
+
+	pullRequest, err := p.store.PullRequestGet(ctx, prID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch principal.Role {
+	case auth.RoleMember, auth.RoleTeamLead:
+		if principal.Subject == pullRequest.AuthorId ||
+			slices.Contains(pullRequest.AssignedReviewers, principal.Subject) {
+			return nil
+		}
+		if principal.Role != auth.RoleTeamLead {
+			return ErrForbidden
+		}
+	case auth.RoleBot:
+		if principal.TeamName == "" {
+			return nil
+		}
+	default:
+		return ErrForbidden
+	}
+
+	// команда PR — та, из которой назначены ревьюверы; у старых PR её может не быть
+	prTeam := pullRequest.TeamName
+	if prTeam == "" {
+		authorTeam, err := p.store.GetAuthorTeam(ctx, pullRequest.AuthorId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		prTeam = authorTeam
+	}
+	if prTeam != "" && prTeam == principal.TeamName {
+		return nil
+	}
+	return ErrForbidden
+}
+
+func (p *Policy) isLeadOf(principal auth.Principal, teamName string) bool {
+	return principal.Role == auth.RoleTeamLead &&
+		principal.TeamName != "" &&
+		principal.TeamName == teamName
+}
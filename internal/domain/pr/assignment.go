@@ -0,0 +1,327 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// maxReviewers — сколько ревьюверов назначается на PR
+const maxReviewers = 2
+
+// ExclusionReason — почему участник команды не попал в кандидаты.
+type ExclusionReason string
+
+const (
+	ExclusionAuthor         ExclusionReason = "author"
+	ExclusionInactive       ExclusionReason = "inactive"
+	ExclusionInactiveInTeam ExclusionReason = "inactive_in_team"
+	ExclusionAtCapacity     ExclusionReason = "at_capacity"
+)
+
+// PickReason — по какому правилу ревьювер занял место.
+type PickReason string
+
+const (
+	PickLead         PickReason = "lead"
+	PickFallbackLead PickReason = "fallback_lead"
+	PickCodeOwner    PickReason = "code_owner"
+	PickSkill        PickReason = "skill"
+	PickPool         PickReason = "pool"
+)
+
+// HistoryEvent — событие в истории назначений PR.
+type HistoryEvent string
+
+const (
+	HistoryCreated HistoryEvent = "created"
+	// HistoryReassigned — ревьювера заменили запросом на переназначение
+	HistoryReassigned HistoryEvent = "reassigned"
+	// HistoryReleased — ревьювер ушёл из команды PR или был анонимизирован
+	HistoryReleased HistoryEvent = "released"
+)
+
+type AssignmentExclusion struct {
+	UserId   string
+	TeamName string
+	Reason   ExclusionReason
+}
+
+type AssignmentPick struct {
+	UserId string
+	Reason PickReason
+}
+
+// ReviewerChange — замена ревьювера после создания PR.
+// Пустой NewUserId — замену найти не удалось.
+type ReviewerChange struct {
+	Event     HistoryEvent
+	OldUserId string
+	NewUserId string
+	At        time.Time
+}
+
+// AssignmentExplanation — как были выбраны ревьюверы PR. AssignedAt — момент
+// подбора, от которого считались рабочее время и окно прошлых пар. По Seed,
+// AssignedAt и тем же данным в хранилище подбор повторяется с тем же
+// результатом.
+type AssignmentExplanation struct {
+	PullRequestId string
+	TeamName      string
+	// PoolTeam — команда, из которой брались кандидаты; отличается
+	// от TeamName, если подбор ушёл в родительскую команду
+	PoolTeam           string
+	Seed               int64
+	AssignedAt         time.Time
+	SelectionMode      SelectionMode
+	PreferWorkingHours bool
+	// Candidates — свободные кандидаты в порядке режима подбора
+	Candidates []string
+	Exclusions []AssignmentExclusion
+	Picks      []AssignmentPick
+	CreatedAt  time.Time
+	Changes    []ReviewerChange
+}
+
+// AssignmentPreview — ревьюверы, которых получил бы PR, если создать его
+// сейчас. В режиме random и при равных кандидатах выбор при создании может
+// отличаться: зерно у созданного PR будет своё.
+type AssignmentPreview struct {
+	AssignmentExplanation
+	Warnings []string
+}
+
+// assignment — результат подбора: ревьюверы, непокрытые навыки и обоснование.
+type assignment struct {
+	reviewers   []string
+	uncovered   []string
+	explanation AssignmentExplanation
+}
+
+// seededRand — источник случайности подбора одного PR.
+func seededRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), 0))
+}
+
+// nextSeed берёт зерно подбора из источника сервиса.
+func (s *service) nextSeed(src *rand.Rand) int64 {
+	s.randMu.Lock()
+	defer s.randMu.Unlock()
+	return src.Int64()
+}
+
+// assignReviewers подбирает ревьюверов PR. Вся случайность берётся из
+// seed, текущее время — из now. load — распределение уже подобранных PR
+// пакета, вне пакета nil. TeamName в обосновании заполняется и при ошибке,
+// если команда уже определена.
+func (s *service) assignReviewers(ctx context.Context, pr PullRequest, seed int64, now time.Time, load *batchLoad) (assignment, error) {
+	rng := seededRand(seed)
+	a := assignment{explanation: AssignmentExplanation{PullRequestId: pr.PullRequestId, Seed: seed, AssignedAt: now}}
+
+	teamName, err := s.reviewTeam(ctx, pr)
+	if err != nil {
+		return a, err
+	}
+	a.explanation.TeamName = teamName
+	a.explanation.PoolTeam = teamName
+	s.log.Info("author team", slog.String("TEAM NAME", teamName))
+
+	leads, err := s.storage.TeamLeadReviewers(ctx, teamName)
+	if err != nil {
+		return a, err
+	}
+	required, err := s.requiredLeads(ctx, pr, leads)
+	if err != nil {
+		return a, err
+	}
+	owners, ownersExcluded, err := s.codeOwners(ctx, rng, teamName, pr)
+	if err != nil {
+		return a, err
+	}
+
+	leadReason := PickLead
+	freeUsers, err := s.storage.GetFreeReviewers(ctx, teamName, pr.AuthorId)
+	if noReviewers(err) {
+		// если и выше никого нет, остаётся исходная ошибка хранилища
+		if escalated, parent, escErr := s.escalate(ctx, teamName, pr.AuthorId); escErr != nil {
+			err = escErr
+		} else if escalated != nil {
+			freeUsers, err = escalated, nil
+			a.explanation.PoolTeam = parent
+		}
+	}
+	if noReviewers(err) {
+		// больше некого назначить — остаются лиды с правилом fallback
+		if len(required) == 0 && len(owners) == 0 {
+			required = fallbackLeads(leads, pr.AuthorId)
+			leadReason = PickFallbackLead
+		}
+		if len(required) > 0 || len(owners) > 0 {
+			freeUsers, err = nil, nil
+		}
+	}
+	if err != nil {
+		return a, err
+	}
+
+	var loaded []AssignmentExclusion
+	if load != nil && !ReviewLimitsIgnored(ctx) {
+		leadsLoaded, err := s.loadedUsers(ctx, teamName, slices.Concat(required, owners), load.open)
+		if err != nil {
+			return a, err
+		}
+		poolIDs := make([]string, 0, len(freeUsers))
+		for _, u := range freeUsers {
+			poolIDs = append(poolIDs, u.UserId)
+		}
+		poolLoaded, err := s.loadedUsers(ctx, a.explanation.PoolTeam, poolIDs, load.open)
+		if err != nil {
+			return a, err
+		}
+		loaded = append(leadsLoaded, poolLoaded...)
+		isLoaded := func(id string) bool {
+			return slices.ContainsFunc(loaded, func(e AssignmentExclusion) bool { return e.UserId == id })
+		}
+		full := len(loaded) > 0
+		required = slices.DeleteFunc(required, isLoaded)
+		owners = slices.DeleteFunc(owners, isLoaded)
+		freeUsers = slices.DeleteFunc(freeUsers, func(u User) bool { return isLoaded(u.UserId) })
+		if full && len(required) == 0 && len(owners) == 0 && len(freeUsers) == 0 {
+			return a, fmt.Errorf("reviewers at capacity within batch: %w", ErrAllAtCapacity)
+		}
+	}
+
+	// исключённые участники нужны только для обоснования
+	for _, team := range slices.Compact([]string{teamName, a.explanation.PoolTeam}) {
+		excluded, err := s.storage.ReviewerExclusions(ctx, team, pr.AuthorId)
+		if err != nil {
+			return a, err
+		}
+		a.explanation.Exclusions = append(a.explanation.Exclusions, excluded...)
+	}
+	// владельцы кода из других команд и участники, занятые в пределах
+	// пакета, в исключения команд не попадают
+	for _, e := range slices.Concat(ownersExcluded, loaded) {
+		if !slices.ContainsFunc(a.explanation.Exclusions, func(x AssignmentExclusion) bool { return x.UserId == e.UserId }) {
+			a.explanation.Exclusions = append(a.explanation.Exclusions, e)
+		}
+	}
+
+	// места занимают по очереди лиды по правилам, владельцы кода,
+	// участники с недостающими навыками и случайные участники команды
+	pick := func(id string, reason PickReason) {
+		a.reviewers = append(a.reviewers, id)
+		a.explanation.Picks = append(a.explanation.Picks, AssignmentPick{UserId: id, Reason: reason})
+	}
+	for _, id := range required {
+		if len(a.reviewers) >= maxReviewers {
+			break
+		}
+		pick(id, leadReason)
+	}
+	for _, id := range owners {
+		if len(a.reviewers) >= maxReviewers {
+			break
+		}
+		if slices.Contains(a.reviewers, id) {
+			continue
+		}
+		pick(id, PickCodeOwner)
+	}
+	freeUsers = slices.DeleteFunc(freeUsers, func(u User) bool {
+		return slices.Contains(a.reviewers, u.UserId)
+	})
+	// порядок задаёт режим подбора команды; по нему же решаются
+	// равные случаи при покрытии навыков
+	freeUsers, selection, err := s.orderCandidates(ctx, rng, now, teamName, pr.AuthorId, freeUsers)
+	if err != nil {
+		return a, err
+	}
+	if load != nil {
+		slices.SortStableFunc(freeUsers, func(a, b User) int {
+			return load.balance[a.UserId] - load.balance[b.UserId]
+		})
+	}
+	a.explanation.SelectionMode = selection.Mode
+	a.explanation.PreferWorkingHours = selection.PreferWorkingHours
+	a.explanation.Candidates = make([]string, 0, len(freeUsers))
+	for _, u := range freeUsers {
+		a.explanation.Candidates = append(a.explanation.Candidates, u.UserId)
+	}
+
+	skilled, uncovered, err := s.coverSkills(ctx, pr.RequiredSkills, a.reviewers, freeUsers, maxReviewers-len(a.reviewers))
+	if err != nil {
+		return a, err
+	}
+	a.uncovered = uncovered
+	for _, id := range skilled {
+		pick(id, PickSkill)
+	}
+	freeUsers = slices.DeleteFunc(freeUsers, func(u User) bool {
+		return slices.Contains(skilled, u.UserId)
+	})
+	for _, user := range freeUsers {
+		if len(a.reviewers) >= maxReviewers {
+			break
+		}
+		pick(user.UserId, PickPool)
+	}
+
+	if len(a.reviewers) == 0 {
+		return a, fmt.Errorf("no available reviewers in team: %w", ErrNoCandidate)
+	}
+	return a, nil
+}
+
+// loadedUsers — участники из ids, у которых открытые ревью вместе с
+// распределёнными в пакете load достигли лимита: личного или команды team.
+func (s *service) loadedUsers(ctx context.Context, team string, ids []string, load map[string]int) ([]AssignmentExclusion, error) {
+	// без загрузки в пакете участника уже отфильтровало хранилище
+	ids = slices.DeleteFunc(slices.Clone(ids), func(id string) bool { return load[id] == 0 })
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	left, err := s.storage.OpenReviewsLeft(ctx, team, ids)
+	if err != nil {
+		return nil, err
+	}
+	var loaded []AssignmentExclusion
+	for _, id := range ids {
+		if n, ok := left[id]; ok && load[id] >= n {
+			loaded = append(loaded, AssignmentExclusion{UserId: id, TeamName: team, Reason: ExclusionAtCapacity})
+		}
+	}
+	return loaded, nil
+}
+
+func (s *service) PullRequestExplainAssignment(ctx context.Context, id string) (AssignmentExplanation, error) {
+	const op = "service.PullRequestExplainAssignment"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	explanation, err := s.storage.AssignmentExplanation(ctx, id)
+	if err != nil {
+		return AssignmentExplanation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return explanation, nil
+}
+
+func (s *service) PullRequestPreviewAssignment(ctx context.Context, pr PullRequest) (AssignmentPreview, error) {
+	const op = "service.PullRequestPreviewAssignment"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	a, err := s.assignReviewers(ctx, pr, s.nextSeed(s.previewRand), time.Now(), nil)
+	if err != nil {
+		return AssignmentPreview{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return AssignmentPreview{
+		AssignmentExplanation: a.explanation,
+		Warnings:              skillWarnings(a.uncovered),
+	}, nil
+}
@@ -0,0 +1,178 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+)
+
+const (
+	// MaxBulkCreate — сколько PR можно передать в один пакет
+	MaxBulkCreate = 1000
+	// bulkChunkSize — сколько PR пакета записывается одной транзакцией
+	bulkChunkSize = 100
+)
+
+// ignoreLimitsKey — ключ контекста подбора без лимита открытых ревью
+type ignoreLimitsKey struct{}
+
+// withoutReviewLimits помечает подбор для уже смерженного PR: лимит
+// открытых ревью к нему не относится.
+func withoutReviewLimits(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ignoreLimitsKey{}, true)
+}
+
+// ReviewLimitsIgnored — подбор идёт без учёта лимита открытых ревью.
+func ReviewLimitsIgnored(ctx context.Context) bool {
+	ignored, _ := ctx.Value(ignoreLimitsKey{}).(bool)
+	return ignored
+}
+
+// batchLoad — сколько PR пакета досталось каждому участнику. balance
+// считает все PR: по нему свободные места достаются менее загруженным.
+// open считает только открытые PR: вместе с уже записанными ревью они
+// занимают лимит открытых ревью.
+type batchLoad struct {
+	balance map[string]int
+	open    map[string]int
+}
+
+func newBatchLoad() *batchLoad {
+	return &batchLoad{balance: make(map[string]int), open: make(map[string]int)}
+}
+
+func (l *batchLoad) add(p PullRequest) {
+	for _, id := range p.AssignedReviewers {
+		l.balance[id]++
+		if p.Status != "MERGED" {
+			l.open[id]++
+		}
+	}
+}
+
+func (l *batchLoad) clone() *batchLoad {
+	return &batchLoad{balance: maps.Clone(l.balance), open: maps.Clone(l.open)}
+}
+
+// BulkCreateItem — PR пакета с обоснованием подбора, готовый к записи.
+type BulkCreateItem struct {
+	PullRequest PullRequest
+	Explanation AssignmentExplanation
+}
+
+// BulkCreateResult — итог по одному PR пакета; Err == nil — PR создан.
+type BulkCreateResult struct {
+	PullRequest PullRequest
+	Err         error
+}
+
+// PullRequestBulkCreate создаёт пакет PR, в том числе уже смерженных, с их
+// исходными created_at и merged_at. Ревьюверы подбираются по обычным
+// правилам, но свободные места в пределах пакета, в том числе среди
+// смерженных PR, достаются менее загруженным участникам. Лимит открытых
+// ревью учитывает и уже распределённые в пакете открытые PR; смерженные PR
+// подбираются без лимита и его не занимают. PR записываются частями по
+// bulkChunkSize, каждая в своей транзакции; в загрузку пакета попадают
+// только записанные PR. Ошибки отдельных PR возвращаются в их результатах;
+// ошибка всего вызова — только отмена контекста.
+func (s *service) PullRequestBulkCreate(ctx context.Context, prs []PullRequest) ([]BulkCreateResult, error) {
+	const op = "service.PullRequestBulkCreate"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	results := make([]BulkCreateResult, len(prs))
+	load := newBatchLoad()
+	seen := make(map[string]bool, len(prs))
+
+	for start := 0; start < len(prs); start += bulkChunkSize {
+		end := min(start+bulkChunkSize, len(prs))
+
+		ids := make([]string, 0, end-start)
+		for _, p := range prs[start:end] {
+			ids = append(ids, p.PullRequestId)
+		}
+		existing, err := s.storage.PullRequestsExisting(ctx, ids)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("%s: %w", op, ctx.Err())
+			}
+			for i := start; i < end; i++ {
+				results[i] = BulkCreateResult{PullRequest: prs[i], Err: err}
+			}
+			continue
+		}
+
+		// часть подбирается с учётом своих же PR, но в общую загрузку
+		// попадает только после записи
+		pending := load.clone()
+		var items []BulkCreateItem
+		var indexes []int
+		for i := start; i < end; i++ {
+			p := prs[i]
+			results[i].PullRequest = p
+			if seen[p.PullRequestId] || slices.Contains(existing, p.PullRequestId) {
+				results[i].Err = ErrPullRequestExists
+				continue
+			}
+			seen[p.PullRequestId] = true
+
+			assignCtx := ctx
+			if p.Status == "MERGED" {
+				assignCtx = withoutReviewLimits(ctx)
+			}
+			a, err := s.assignReviewers(assignCtx, p, s.nextSeed(s.rand), time.Now(), pending)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, fmt.Errorf("%s: %w", op, ctx.Err())
+				}
+				s.noCandidate(ctx, a.explanation.TeamName, err)
+				results[i].Err = err
+				continue
+			}
+			p.AssignedReviewers = a.reviewers
+			p.TeamName = a.explanation.TeamName
+			p.Version = InitialVersion
+			if len(a.uncovered) > 0 {
+				p.Warnings = skillWarnings(a.uncovered)
+			}
+			pending.add(p)
+			items = append(items, BulkCreateItem{PullRequest: p, Explanation: a.explanation})
+			indexes = append(indexes, i)
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		raced, err := s.storage.PullRequestBulkCreate(ctx, items)
+		if err != nil && ctx.Err() != nil {
+			return nil, fmt.Errorf("%s: %w", op, ctx.Err())
+		}
+		for k, i := range indexes {
+			switch {
+			case err != nil:
+				results[i].Err = err
+			case slices.Contains(raced, items[k].PullRequest.PullRequestId):
+				// PR успели создать другим запросом между проверкой и записью
+				results[i].Err = ErrPullRequestExists
+			default:
+				results[i].PullRequest = items[k].PullRequest
+				load.add(items[k].PullRequest)
+				s.metrics.PullRequestCreated()
+			}
+		}
+	}
+
+	created := 0
+	for _, r := range results {
+		if r.Err == nil {
+			created++
+		}
+	}
+	s.log.Info("pull requests bulk created", slog.Int("total", len(prs)), slog.Int("created", created))
+
+	return results, nil
+}
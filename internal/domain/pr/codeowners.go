@@ -0,0 +1,223 @@
+package pr
+
+import (
+	"context"
+	"maps"
+	"math/rand/v2"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ParseCodeowners разбирает файл в формате CODEOWNERS: строка — шаблон пути
+// и владельцы через пробел. `@user_id` — пользователь, `@org/team_name` —
+// команда. Шаблон без владельцев снимает владельцев с подходящих файлов.
+// Пустые строки и строки с `#` в начале пропускаются.
+func ParseCodeowners(content string) ([]CodeownersRule, []CodeownersError) {
+	var (
+		rules []CodeownersRule
+		errs  []CodeownersError
+	)
+	for i, raw := range strings.Split(content, "\n") {
+		line := i + 1
+		fields := strings.Fields(raw)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		rule := CodeownersRule{Line: line, Pattern: fields[0]}
+		if msg := checkCodeownersPattern(rule.Pattern); msg != "" {
+			errs = append(errs, CodeownersError{Line: line, Message: msg})
+			continue
+		}
+
+		valid := true
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			name, ok := strings.CutPrefix(owner, "@")
+			if !ok || name == "" {
+				errs = append(errs, CodeownersError{Line: line, Message: "владелец должен начинаться с @: " + owner})
+				valid = false
+				continue
+			}
+			if slash := strings.LastIndex(name, "/"); slash >= 0 {
+				if name[slash+1:] == "" {
+					errs = append(errs, CodeownersError{Line: line, Message: "пустое имя команды: " + owner})
+					valid = false
+					continue
+				}
+				rule.Teams = append(rule.Teams, name[slash+1:])
+				continue
+			}
+			rule.Users = append(rule.Users, name)
+		}
+		if valid {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, errs
+}
+
+func checkCodeownersPattern(pattern string) string {
+	if strings.HasPrefix(pattern, "!") {
+		return "отрицание шаблонов не поддерживается: " + pattern
+	}
+	if strings.Trim(pattern, "/") == "" {
+		return "пустой шаблон: " + pattern
+	}
+	for _, seg := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			return "некорректный шаблон: " + pattern
+		}
+	}
+	return ""
+}
+
+// CodeownersOwners возвращает владельцев файла: действует последнее
+// подходящее правило, как в CODEOWNERS. ok == false — ни одно правило не подошло.
+func CodeownersOwners(rules []CodeownersRule, file string) (rule CodeownersRule, ok bool) {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if codeownersMatch(rules[i].Pattern, file) {
+			return rules[i], true
+		}
+	}
+	return CodeownersRule{}, false
+}
+
+// codeownersMatch сопоставляет путь с шаблоном по правилам gitignore:
+// шаблон с `/` в начале или в середине привязан к корню, иначе ищется
+// на любой глубине; `**` — любое число каталогов; шаблон, совпавший
+// с каталогом, покрывает всё его содержимое; `/` в конце — только каталоги.
+func codeownersMatch(pattern, file string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+
+	segs := strings.Split(trimmed, "/")
+	if !anchored {
+		segs = append([]string{"**"}, segs...)
+	}
+	return matchSegments(segs, strings.Split(strings.Trim(file, "/"), "/"), !dirOnly)
+}
+
+// matchSegments проверяет, что шаблон покрывает путь целиком (если allowFull)
+// или один из каталогов на пути к нему.
+func matchSegments(pattern, file []string, allowFull bool) bool {
+	if len(pattern) == 0 {
+		if len(file) == 0 {
+			return allowFull
+		}
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(file); i++ {
+			if matchSegments(pattern[1:], file[i:], allowFull) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(file) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], file[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], file[1:], allowFull)
+}
+
+// codeOwners возвращает доступных владельцев изменённых файлов PR. Раньше
+// идут владельцы по правилам, под которые попало больше файлов; внутри
+// правила порядок задаёт rng. Владельцы с исчерпанным лимитом открытых
+// ревью возвращаются исключёнными.
+func (s *service) codeOwners(ctx context.Context, rng *rand.Rand, teamName string, pr PullRequest) ([]string, []AssignmentExclusion, error) {
+	if len(pr.ChangedFiles) == 0 {
+		return nil, nil, nil
+	}
+	rules, err := s.storage.TeamCodeowners(ctx, teamName)
+	if err != nil || len(rules) == 0 {
+		return nil, nil, err
+	}
+
+	matched := make(map[int]int)
+	byLine := make(map[int]CodeownersRule)
+	for _, file := range pr.ChangedFiles {
+		rule, ok := CodeownersOwners(rules, file)
+		if !ok || len(rule.Users)+len(rule.Teams) == 0 {
+			continue
+		}
+		matched[rule.Line]++
+		byLine[rule.Line] = rule
+	}
+	lines := slices.Collect(maps.Keys(matched))
+	slices.SortFunc(lines, func(a, b int) int {
+		if matched[a] != matched[b] {
+			return matched[b] - matched[a]
+		}
+		return a - b
+	})
+
+	var owners []string
+	var excluded []AssignmentExclusion
+	for _, line := range lines {
+		rule := byLine[line]
+		available, atCapacity, err := s.storage.AvailableOwners(ctx, teamName, rule.Users, rule.Teams, pr.AuthorId)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, id := range atCapacity {
+			if !slices.ContainsFunc(excluded, func(e AssignmentExclusion) bool { return e.UserId == id }) {
+				excluded = append(excluded, AssignmentExclusion{UserId: id, TeamName: teamName, Reason: ExclusionAtCapacity})
+			}
+		}
+		rng.Shuffle(len(available), func(i, j int) {
+			available[i], available[j] = available[j], available[i]
+		})
+		for _, id := range available {
+			if !slices.Contains(owners, id) {
+				owners = append(owners, id)
+			}
+		}
+	}
+	return owners, excluded, nil
+}
+
+// checkCodeowners разбирает файл и добавляет в отчёт ошибки
+// о несуществующих владельцах.
+func (s *service) checkCodeowners(ctx context.Context, r CodeownersUpload) (CodeownersReport, error) {
+	if _, err := s.storage.TeamGet(ctx, r.TeamName); err != nil {
+		return CodeownersReport{}, err
+	}
+
+	report := CodeownersReport{TeamName: r.TeamName}
+	report.Rules, report.Errors = ParseCodeowners(r.Content)
+
+	var users, teams []string
+	for _, rule := range report.Rules {
+		users = append(users, rule.Users...)
+		teams = append(teams, rule.Teams...)
+	}
+	if len(users)+len(teams) == 0 {
+		return report, nil
+	}
+	unknownUsers, unknownTeams, err := s.storage.UnknownOwners(ctx, users, teams)
+	if err != nil {
+		return CodeownersReport{}, err
+	}
+	for _, rule := range report.Rules {
+		for _, u := range rule.Users {
+			if slices.Contains(unknownUsers, u) {
+				report.Errors = append(report.Errors, CodeownersError{Line: rule.Line, Message: "пользователь не найден: @" + u})
+			}
+		}
+		for _, t := range rule.Teams {
+			if slices.Contains(unknownTeams, t) {
+				report.Errors = append(report.Errors, CodeownersError{Line: rule.Line, Message: "команда не найдена: " + t})
+			}
+		}
+	}
+	slices.SortStableFunc(report.Errors, func(a, b CodeownersError) int { return a.Line - b.Line })
+	return report, nil
+}
@@ -0,0 +1,143 @@
+package pr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCodeownersMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		file    string
+		want    bool
+	}{
+		{"без / — на любой глубине", "*.go", "internal/domain/pr/service.go", true},
+		{"без / — в корне", "*.go", "main.go", true},
+		{"без / — другое расширение", "*.go", "README.md", false},
+		{"имя каталога без / — на любой глубине", "docs", "api/docs/index.md", true},
+		{"/ в начале — только от корня", "/docs", "docs/index.md", true},
+		{"/ в начале — не в подкаталоге", "/docs", "api/docs/index.md", false},
+		{"/ в середине — привязан к корню", "internal/domain", "internal/domain/pr/model.go", true},
+		{"/ в середине — не в подкаталоге", "domain/pr", "internal/domain/pr/model.go", false},
+		{"каталог покрывает содержимое", "/internal", "internal/a/b/c.go", true},
+		{"префикс имени — не каталог", "/intern", "internal/a.go", false},
+		{"** в начале", "**/migrations", "internal/storage/migrations/001.sql", true},
+		{"** в начале — в корне", "**/migrations", "migrations/001.sql", true},
+		{"** в середине — ноль каталогов", "internal/**/model.go", "internal/model.go", true},
+		{"** в середине — несколько каталогов", "internal/**/model.go", "internal/domain/pr/model.go", true},
+		{"** в середине — другой корень", "internal/**/model.go", "cmd/domain/model.go", false},
+		{"** в конце", "internal/**", "internal/domain/pr/model.go", true},
+		{"/ в конце — каталог", "build/", "build/out/app", true},
+		{"/ в конце — файл с тем же именем", "build/", "tools/build", false},
+		{"/ в конце — каталог на любой глубине", "build/", "tools/build/app", true},
+		{"без / в конце — файл с тем же именем", "build", "tools/build", true},
+		{"? и классы символов", "/cmd/pr?/main.[gG]o", "cmd/prctl/main.go", false},
+		{"? и классы символов — совпадение", "/cmd/pr?/main.[gG]o", "cmd/prx/main.Go", true},
+		{"* не переходит через /", "/cmd/*.go", "cmd/pr/main.go", false},
+		{"файл с / по краям", "/README.md", "/README.md", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codeownersMatch(tt.pattern, tt.file); got != tt.want {
+				t.Errorf("codeownersMatch(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeownersOwners(t *testing.T) {
+	rules, errs := ParseCodeowners(`
+# владельцы по умолчанию
+*                  @lead
+*.go               @u1 @org/backend
+/internal/storage/ @u2
+/internal/storage/migrations
+docs/              @org/docs # комментарий после владельцев
+`)
+	if len(errs) > 0 {
+		t.Fatalf("ParseCodeowners errors: %v", errs)
+	}
+
+	tests := []struct {
+		name   string
+		file   string
+		want   CodeownersRule
+		wantOk bool
+	}{
+		{
+			name:   "действует последнее подходящее правило",
+			file:   "cmd/pr/main.go",
+			want:   CodeownersRule{Line: 4, Pattern: "*.go", Users: []string{"u1"}, Teams: []string{"backend"}},
+			wantOk: true,
+		},
+		{
+			name:   "каталог позже маски",
+			file:   "internal/storage/postgres.go",
+			want:   CodeownersRule{Line: 5, Pattern: "/internal/storage/", Users: []string{"u2"}},
+			wantOk: true,
+		},
+		{
+			name:   "правило без владельцев снимает их",
+			file:   "internal/storage/migrations/001.sql",
+			want:   CodeownersRule{Line: 6, Pattern: "/internal/storage/migrations"},
+			wantOk: true,
+		},
+		{
+			name:   "правило по умолчанию",
+			file:   "Makefile",
+			want:   CodeownersRule{Line: 3, Pattern: "*", Users: []string{"lead"}},
+			wantOk: true,
+		},
+		{
+			name:   "комментарий после владельцев",
+			file:   "docs/index.md",
+			want:   CodeownersRule{Line: 7, Pattern: "docs/", Teams: []string{"docs"}},
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CodeownersOwners(rules, tt.file)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CodeownersOwners(%q) = %+v, %v, want %+v, %v", tt.file, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	if _, ok := CodeownersOwners(rules[1:], "Makefile"); ok {
+		t.Errorf("CodeownersOwners without default rule matched Makefile")
+	}
+}
+
+func TestParseCodeownersErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		rules   int
+		errs    []int
+	}{
+		{"пустые строки и комментарии", "\n# comment\n   \n", 0, nil},
+		{"отрицание", "!*.go @u1", 0, []int{1}},
+		{"пустой шаблон", "/ @u1", 0, []int{1}},
+		{"некорректный шаблон", "[a-.go @u1", 0, []int{1}},
+		{"владелец без @", "*.go u1", 0, []int{1}},
+		{"пустое имя команды", "*.go @org/", 0, []int{1}},
+		{"ошибка не мешает остальным строкам", "*.go @u1\n*.md owner\n/docs @u2", 2, []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, errs := ParseCodeowners(tt.content)
+			if len(rules) != tt.rules {
+				t.Errorf("rules = %d, want %d", len(rules), tt.rules)
+			}
+			var lines []int
+			for _, e := range errs {
+				lines = append(lines, e.Line)
+			}
+			if !reflect.DeepEqual(lines, tt.errs) {
+				t.Errorf("error lines = %v, want %v (%v)", lines, tt.errs, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package pr
+
+import "errors"
+
+// Доменные ошибки. Слой хранения оборачивает их в ошибки с кодами API,
+// поэтому сервис может проверять их через errors.Is, не завися от postgres.
+var (
+	ErrNoCandidate = errors.New("no candidate")
+	// ErrAllAtCapacity — активные кандидаты есть, но у всех исчерпан лимит открытых ревью.
+	ErrAllAtCapacity = errors.New("all reviewers at capacity")
+	// ErrVersionMismatch — PR изменился после того, как клиент получил его версию.
+	ErrVersionMismatch = errors.New("version mismatch")
+	// ErrNotTeamMember — автор PR не состоит в указанной команде.
+	ErrNotTeamMember = errors.New("author is not a member of the team")
+	// ErrNotTeamLead — правила назначаются только участнику с ролью lead.
+	ErrNotTeamLead = errors.New("user is not a lead of the team")
+	// ErrPullRequestExists — PR с таким id уже есть или повторяется в пакете.
+	ErrPullRequestExists = errors.New("pull request already exists")
+	// ErrInvalidCodeowners — в CODEOWNERS есть ошибки, файл не сохранён.
+	ErrInvalidCodeowners = errors.New("invalid codeowners")
+	// ErrInvalidWorkingHours — в расписании нет рабочих дней или начало дня не раньше конца.
+	ErrInvalidWorkingHours = errors.New("invalid working hours")
+)
@@ -0,0 +1,70 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PullRequestsFilter — фильтры выгрузки PR; nil-поля не ограничивают.
+type PullRequestsFilter struct {
+	Status   *string
+	TeamName *string
+	AuthorId *string
+	// CreatedFrom и CreatedTo — полуинтервал [CreatedFrom, CreatedTo) по created_at
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+// TeamExport — строка выгрузки команды: настройки и число участников без состава.
+type TeamExport struct {
+	TeamName           string
+	ParentTeam         string
+	SelectionMode      SelectionMode
+	MaxOpenReviews     int
+	PreferWorkingHours bool
+	Members            int
+	ActiveMembers      int
+}
+
+// Выгрузки читают строки из хранилища по одной и сразу передают их в fn,
+// не собирая результат в памяти. Ошибка fn прекращает выгрузку.
+
+func (s *service) ExportPullRequests(ctx context.Context, f PullRequestsFilter, fn func(PullRequest) error) error {
+	const op = "service.ExportPullRequests"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	if err := s.storage.ExportPullRequests(ctx, f, fn); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ExportUsers выгружает пользователей с теми же фильтрами, что и UsersList;
+// Limit не учитывается, After задаёт начало выгрузки.
+func (s *service) ExportUsers(ctx context.Context, f UsersListFilter, fn func(User) error) error {
+	const op = "service.ExportUsers"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	if err := s.storage.ExportUsers(ctx, f, fn); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ExportTeams выгружает команды поддерева root или все команды, если root пустой.
+func (s *service) ExportTeams(ctx context.Context, root string, fn func(TeamExport) error) error {
+	const op = "service.ExportTeams"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	if err := s.storage.ExportTeams(ctx, root, fn); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package pr
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// requiredLeads возвращает лидов, правила которых совпали с PR. Автор PR
+// в ревьюверы не попадает, даже если он лид. Грейд автора читается,
+// только если у кого-то из лидов есть правило author_seniority.
+func (s *service) requiredLeads(ctx context.Context, pr PullRequest, leads []LeadReviewer) ([]string, error) {
+	var seniority Seniority
+	if slices.ContainsFunc(leads, func(l LeadReviewer) bool {
+		return slices.ContainsFunc(l.Rules, func(r LeadRule) bool { return r.Kind == LeadRuleAuthorSeniority })
+	}) {
+		author, err := s.storage.UserGet(ctx, pr.AuthorId)
+		if err != nil {
+			return nil, err
+		}
+		seniority = author.Seniority
+	}
+
+	var ids []string
+	for _, l := range leads {
+		if l.UserId == pr.AuthorId {
+			continue
+		}
+		if slices.ContainsFunc(l.Rules, func(r LeadRule) bool { return r.matches(pr, seniority) }) {
+			ids = append(ids, l.UserId)
+		}
+	}
+	return ids, nil
+}
+
+// fallbackLeads — лиды с правилом fallback, кроме автора PR.
+func fallbackLeads(leads []LeadReviewer, authorID string) []string {
+	var ids []string
+	for _, l := range leads {
+		if l.UserId == authorID {
+			continue
+		}
+		if slices.ContainsFunc(l.Rules, func(r LeadRule) bool { return r.Kind == LeadRuleFallback }) {
+			ids = append(ids, l.UserId)
+		}
+	}
+	return ids
+}
+
+func (r LeadRule) matches(pr PullRequest, authorSeniority Seniority) bool {
+	switch r.Kind {
+	case LeadRuleNamePrefix:
+		return strings.HasPrefix(pr.PullRequestName, r.Value)
+	case LeadRuleLabel:
+		return slices.Contains(pr.Labels, r.Value)
+	case LeadRuleAuthorSeniority:
+		return authorSeniority != "" && string(authorSeniority) == r.Value
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package pr
+
+// Причины, по которым PR остался без ревьювера: кандидатов нет вовсе или
+// у всех исчерпан лимит открытых ревью.
+const (
+	ReasonNoCandidate   = "no_candidate"
+	ReasonAllAtCapacity = "all_at_capacity"
+)
+
+// Metrics собирает доменные счётчики сервиса.
+type Metrics interface {
+	PullRequestCreated()
+	PullRequestMerged()
+	PullRequestReassigned()
+	// NoCandidate — не нашлось ни одного ревьювера в команде; reason — ReasonNoCandidate
+	// или ReasonAllAtCapacity
+	NoCandidate(org, team, reason string)
+	// Escalated — ревьюверы взяты из родительской команды, потому что в команде никого нет
+	Escalated(org, team string)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) PullRequestCreated()        {}
+func (nopMetrics) PullRequestMerged()         {}
+func (nopMetrics) PullRequestReassigned()     {}
+func (nopMetrics) NoCandidate(_, _, _ string) {}
+func (nopMetrics) Escalated(_, _ string)      {}
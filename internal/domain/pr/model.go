@@ -11,23 +11,132 @@ type PullRequest struct {
 	PullRequestId     string     
 	PullRequestName   string     
 	Status            string     
+	// Version увеличивается при каждом изменении PR (ETag)
+	Version int64
+	// TeamName — команда, из которой назначаются ревьюверы; при создании
+	// пустое значение означает основную команду автора
+	TeamName string
+	// Labels — метки PR, по ним срабатывают правила лидов
+	Labels []string
+	// ChangedFiles — изменённые файлы; только при создании, для выбора владельцев кода
+	ChangedFiles []string
+	// RequiredSkills — навыки, которые должен покрыть хотя бы один ревьювер; только при создании
+	RequiredSkills []string
+	// Warnings — предупреждения подбора ревьюверов; только в ответе на создание
+	Warnings []string
+	// ReviewAssignedAt и ReviewDueAt — когда запрошенный ревьювер назначен
+	// на PR и срок ревью по SLA; только в GetUsersReview, срок — у открытых PR
+	ReviewAssignedAt *time.Time
+	ReviewDueAt      *time.Time
 }
 
+// InitialVersion — версия только что созданного PR.
+const InitialVersion int64 = 1
+
 type User struct {
 	IsActive bool   
+	// TeamName — основная команда пользователя
 	TeamName string 
 	UserId   string 
 	Username string 
+	// AnonymizedAt — момент анонимизации; nil у обычных пользователей
+	AnonymizedAt *time.Time
+	// Teams — все команды пользователя, включая основную
+	Teams []string
+	// Seniority — грейд; пустой — не указан
+	Seniority Seniority
+	// Skills — навыки в порядке тега
+	Skills []Skill
+	// MaxOpenReviews — личный лимит открытых ревью; 0 — действует лимит команды
+	MaxOpenReviews int
+	// WorkingHours — рабочее время; nil — не задано
+	WorkingHours *WorkingHours
+}
+
+// SkillLevel — уровень владения навыком.
+type SkillLevel string
+
+const (
+	SkillLevelBasic        SkillLevel = "basic"
+	SkillLevelIntermediate SkillLevel = "intermediate"
+	SkillLevelExpert       SkillLevel = "expert"
+)
+
+type Skill struct {
+	Tag   string
+	Level SkillLevel
 }
 
+// UserSetSkills заменяет навыки пользователя; пустой Skills их снимает.
+type UserSetSkills struct {
+	UserId string
+	Skills []Skill
+}
+
+// Seniority — грейд пользователя, критерий правила author_seniority.
+type Seniority string
+
+const (
+	SeniorityJunior Seniority = "junior"
+	SeniorityMiddle Seniority = "middle"
+	SenioritySenior Seniority = "senior"
+)
+
 type PostPullRequestReassign struct {
 	OldUserId     string 
 	PullRequestId string 
+	// ExpectedVersion — версия из If-Match; 0 — без проверки
+	ExpectedVersion int64
 }
 
 type Team struct {
 	Members  []TeamMember 
 	TeamName string       
+	// ParentTeam — родительская команда; пустая у корневых
+	ParentTeam string
+	// SelectionMode — режим подбора ревьюверов; пустой при создании — random
+	SelectionMode SelectionMode
+	// MaxOpenReviews — лимит открытых ревью участника по умолчанию; 0 — без лимита
+	MaxOpenReviews int
+	// PreferWorkingHours — при создании PR раньше идут участники в рабочее время
+	PreferWorkingHours bool
+}
+
+// SelectionMode — как упорядочиваются участники команды при подборе ревьюверов.
+type SelectionMode string
+
+const (
+	// SelectionModeRandom — случайный порядок
+	SelectionModeRandom SelectionMode = "random"
+	// SelectionModeSpread — реже выбирать тех, кто недавно ревьюил автора
+	SelectionModeSpread SelectionMode = "spread"
+)
+
+// TeamSetMaxOpenReviews задаёт лимит команды по умолчанию; 0 его снимает.
+type TeamSetMaxOpenReviews struct {
+	TeamName       string
+	MaxOpenReviews int
+}
+
+// TeamSetSelectionMode меняет подбор ревьюверов команды; пустой Mode
+// и nil PreferWorkingHours оставляют прежние значения.
+type TeamSetSelectionMode struct {
+	TeamName           string
+	Mode               SelectionMode
+	PreferWorkingHours *bool
+}
+
+// TeamSelection — настройки подбора ревьюверов команды.
+type TeamSelection struct {
+	Mode               SelectionMode
+	PreferWorkingHours bool
+}
+
+// Pairing — сколько раз ревьювер назначался на PR автора за период.
+type Pairing struct {
+	ReviewerId string
+	Count      int
+	LastAt     time.Time
 }
 
 // TeamMember defines model for TeamMember.
@@ -35,8 +144,20 @@ type TeamMember struct {
 	IsActive bool   
 	UserId   string 
 	Username string 
+	// Role — роль в команде; пустая при добавлении — не менять (member для новых)
+	Role MembershipRole
+	// LeadRules — правила, по которым лид добавляется ревьювером; только в ответах
+	LeadRules []LeadRule
 }
 
+// MembershipRole — роль пользователя в конкретной команде.
+type MembershipRole string
+
+const (
+	MembershipRoleMember MembershipRole = "member"
+	MembershipRoleLead   MembershipRole = "lead"
+)
+
 type TeamName struct {
 	TeamName string 
 }
@@ -47,4 +168,184 @@ type GetReviewParams struct{
 type UsersSetIsActive struct{
     IsActive bool  
     UserId   string 
-}
\ No newline at end of file
+}
+
+// OpenReviews — что делать с открытыми ревью участников, которые после
+// изменения состава больше не состоят в команде автора PR.
+type OpenReviews string
+
+const (
+	// OpenReviewsKeep — оставить ревьювера как есть
+	OpenReviewsKeep OpenReviews = "keep"
+	// OpenReviewsReassign — заменить активным участником команды автора,
+	// а если замены нет — снять ревьювера
+	OpenReviewsReassign OpenReviews = "reassign"
+)
+
+type TeamAddMembers struct {
+	TeamName    string
+	Members     []TeamMember
+	OpenReviews OpenReviews
+}
+
+type TeamRemoveMembers struct {
+	TeamName    string
+	UserIds     []string
+	OpenReviews OpenReviews
+}
+
+// TeamSetMemberActive — участие пользователя в ревью одной команды.
+type TeamSetMemberActive struct {
+	TeamName string
+	UserId   string
+	IsActive bool
+}
+
+// LeadRuleKind — критерий, по которому лид команды добавляется ревьювером PR.
+type LeadRuleKind string
+
+const (
+	// LeadRuleNamePrefix — название PR начинается с Value
+	LeadRuleNamePrefix LeadRuleKind = "name_prefix"
+	// LeadRuleLabel — у PR есть метка Value
+	LeadRuleLabel LeadRuleKind = "label"
+	// LeadRuleAuthorSeniority — грейд автора равен Value
+	LeadRuleAuthorSeniority LeadRuleKind = "author_seniority"
+	// LeadRuleFallback — в команде (и выше по иерархии) больше некого назначить
+	LeadRuleFallback LeadRuleKind = "fallback"
+)
+
+type LeadRule struct {
+	Kind  LeadRuleKind
+	Value string
+}
+
+// TeamSetLeadRules заменяет правила лида в команде; пустой Rules их снимает.
+type TeamSetLeadRules struct {
+	TeamName string
+	UserId   string
+	Rules    []LeadRule
+}
+
+// LeadReviewer — активный лид команды, у которого есть правила.
+type LeadReviewer struct {
+	UserId string
+	Rules  []LeadRule
+}
+
+// CodeownersRule — строка CODEOWNERS команды. Users и Teams пустые —
+// у подходящих файлов владельцев нет.
+type CodeownersRule struct {
+	Line    int
+	Pattern string
+	Users   []string
+	Teams   []string
+}
+
+type CodeownersError struct {
+	Line    int
+	Message string
+}
+
+// CodeownersUpload — содержимое CODEOWNERS для команды.
+type CodeownersUpload struct {
+	TeamName string
+	Content  string
+}
+
+// CodeownersReport — результат разбора; файл принимается, только если Errors пустой.
+type CodeownersReport struct {
+	TeamName string
+	Rules    []CodeownersRule
+	Errors   []CodeownersError
+}
+
+// TeamSetParent — перенос команды в иерархии; пустой ParentTeam делает её корневой.
+type TeamSetParent struct {
+	TeamName   string
+	ParentTeam string
+}
+
+// TeamNode — команда в дереве /team/tree. Members — число участников самой команды.
+type TeamNode struct {
+	TeamName   string
+	ParentTeam string
+	Members    int
+	Children   []TeamNode
+}
+
+// TeamStatsRow — показатели одной команды или поддерева.
+type TeamStatsRow struct {
+	TeamName           string
+	Members            int
+	ActiveMembers      int
+	OpenPullRequests   int
+	MergedPullRequests int
+	OpenReviews        int
+}
+
+// TeamStats — показатели поддерева команды: Total по всему поддереву
+// (участник нескольких команд поддерева считается один раз), Teams — по каждой команде.
+type TeamStats struct {
+	Total TeamStatsRow
+	Teams []TeamStatsRow
+}
+
+type TeamRename struct {
+	TeamName    string
+	NewTeamName string
+}
+
+type TeamDelete struct {
+	TeamName    string
+	OpenReviews OpenReviews
+}
+
+// Reassignment — замена ревьювера PR. Пустой NewUserId — ревьювер снят без замены.
+type Reassignment struct {
+	PullRequestId string
+	OldUserId     string
+	NewUserId     string
+}
+
+type TeamChangeResult struct {
+	Team          Team
+	Reassignments []Reassignment
+}
+
+// UsersListFilter — фильтры списка пользователей. Страница начинается
+// после пользователя After (в порядке user_id).
+type UsersListFilter struct {
+	TeamName *string
+	IsActive *bool
+	After    string
+	Limit    int
+}
+
+// UsersPage — страница списка; пустой NextAfter — страница последняя.
+type UsersPage struct {
+	Users     []User
+	NextAfter string
+}
+
+// UserUpdate — изменение пользователя. nil-поля не меняются,
+// пустой TeamName исключает пользователя из команды, пустой Seniority снимает грейд.
+type UserUpdate struct {
+	UserId      string
+	Username    *string
+	TeamName    *string
+	Seniority   *Seniority
+	// MaxOpenReviews — личный лимит; 0 снимает его
+	MaxOpenReviews *int
+	OpenReviews    OpenReviews
+}
+
+type UserAnonymize struct {
+	UserId      string
+	OpenReviews OpenReviews
+}
+
+type UserChangeResult struct {
+	User          User
+	Reassignments []Reassignment
+}
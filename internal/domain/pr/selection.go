@@ -0,0 +1,90 @@
+package pr
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+const (
+	// PairingWindow — за какой период учитываются прошлые пары в режиме spread
+	PairingWindow = 90 * 24 * time.Hour
+	// pairingHalfLife — через сколько вес прошлой пары уменьшается вдвое
+	pairingHalfLife = 14 * 24 * time.Hour
+)
+
+// orderCandidates упорядочивает кандидатов в ревьюверы по настройкам команды.
+// random — случайный порядок; spread — по возрастанию штрафа за прошлые
+// пары с автором, при равном штрафе — случайно. С PreferWorkingHours
+// участники в рабочее время идут раньше остальных, порядок внутри сохраняется.
+// Рабочее время и давность пар считаются на момент now. Возвращает и сами
+// настройки, по которым упорядочены кандидаты.
+func (s *service) orderCandidates(ctx context.Context, rng *rand.Rand, now time.Time, teamName, authorID string, pool []User) ([]User, TeamSelection, error) {
+	selection, err := s.storage.TeamSelection(ctx, teamName)
+	if err != nil {
+		return nil, TeamSelection{}, err
+	}
+	rng.Shuffle(len(pool), func(i, j int) {
+		pool[i], pool[j] = pool[j], pool[i]
+	})
+	if len(pool) < 2 {
+		return pool, selection, nil
+	}
+
+	if selection.Mode == SelectionModeSpread {
+		if err := s.spreadCandidates(ctx, authorID, pool, now); err != nil {
+			return nil, TeamSelection{}, err
+		}
+	}
+	if selection.PreferWorkingHours {
+		slices.SortStableFunc(pool, func(a, b User) int {
+			switch wa, wb := a.WorkingHours.WorkingAt(now), b.WorkingHours.WorkingAt(now); {
+			case wa && !wb:
+				return -1
+			case !wa && wb:
+				return 1
+			}
+			return 0
+		})
+	}
+	return pool, selection, nil
+}
+
+// spreadCandidates сортирует кандидатов по возрастанию штрафа за пары с автором.
+func (s *service) spreadCandidates(ctx context.Context, authorID string, pool []User, now time.Time) error {
+	ids := make([]string, 0, len(pool))
+	for _, u := range pool {
+		ids = append(ids, u.UserId)
+	}
+	history, err := s.storage.PairHistory(ctx, authorID, ids, now.Add(-PairingWindow))
+	if err != nil {
+		return err
+	}
+
+	penalty := make(map[string]float64, len(history))
+	for id, times := range history {
+		penalty[id] = pairingPenalty(times, now)
+	}
+	slices.SortStableFunc(pool, func(a, b User) int {
+		switch pa, pb := penalty[a.UserId], penalty[b.UserId]; {
+		case pa < pb:
+			return -1
+		case pa > pb:
+			return 1
+		}
+		return 0
+	})
+	return nil
+}
+
+// pairingPenalty — сумма весов прошлых пар; вес свежей пары 1,
+// каждые pairingHalfLife он уменьшается вдвое.
+func pairingPenalty(times []time.Time, now time.Time) float64 {
+	var p float64
+	for _, t := range times {
+		p += math.Exp2(-float64(now.Sub(t)) / float64(pairingHalfLife))
+	}
+	return p
+}
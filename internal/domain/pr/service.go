@@ -2,100 +2,237 @@ package pr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math/rand/v2"
+	"pr-service/internal/domain/auth"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("pr-service/domain/pr")
+
 type Service interface {
 	PullRequestCreate(ctx context.Context, pr PullRequest) (PullRequest, error)
-	PullRequestMerge(ctx context.Context, id string) (PullRequest, error)
+	PullRequestMerge(ctx context.Context, id string, expectedVersion int64) (PullRequest, error)
 	PullRequestReassign(ctx context.Context, r PostPullRequestReassign) (PullRequest, error)
 	TeamAdd(ctx context.Context, r Team) (Team, error)
 	TeamGet(ctx context.Context, r TeamName) (Team, error)
 	GetUsersReview(ctx context.Context, p GetReviewParams) ([]PullRequest, error)
 	UsersSetIsActive(ctx context.Context, u UsersSetIsActive) (error)
+	TeamAddMembers(ctx context.Context, r TeamAddMembers) (TeamChangeResult, error)
+	TeamSetMemberActive(ctx context.Context, r TeamSetMemberActive) (Team, error)
+	TeamSetLeadRules(ctx context.Context, r TeamSetLeadRules) (Team, error)
+	CodeownersValidate(ctx context.Context, r CodeownersUpload) (CodeownersReport, error)
+	CodeownersUpload(ctx context.Context, r CodeownersUpload) (CodeownersReport, error)
+	TeamRemoveMembers(ctx context.Context, r TeamRemoveMembers) (TeamChangeResult, error)
+	TeamRename(ctx context.Context, r TeamRename) (Team, error)
+	TeamSetParent(ctx context.Context, r TeamSetParent) (Team, error)
+	TeamTree(ctx context.Context, root string) ([]TeamNode, error)
+	TeamStats(ctx context.Context, teamName string) (TeamStats, error)
+	TeamDelete(ctx context.Context, r TeamDelete) ([]Reassignment, error)
+	UserGet(ctx context.Context, id string) (User, error)
+	UsersList(ctx context.Context, f UsersListFilter) (UsersPage, error)
+	UserUpdate(ctx context.Context, r UserUpdate) (UserChangeResult, error)
+	UserAnonymize(ctx context.Context, r UserAnonymize) (UserChangeResult, error)
+	UserSetSkills(ctx context.Context, r UserSetSkills) (User, error)
+	UserSetWorkingHours(ctx context.Context, r UserSetWorkingHours) (User, error)
+	TeamSetSelectionMode(ctx context.Context, r TeamSetSelectionMode) (Team, error)
+	TeamSetMaxOpenReviews(ctx context.Context, r TeamSetMaxOpenReviews) (Team, error)
+	UserPairings(ctx context.Context, authorID string) ([]Pairing, error)
+	PullRequestExplainAssignment(ctx context.Context, id string) (AssignmentExplanation, error)
+	PullRequestPreviewAssignment(ctx context.Context, pr PullRequest) (AssignmentPreview, error)
+	PullRequestBulkCreate(ctx context.Context, prs []PullRequest) ([]BulkCreateResult, error)
+	ExportPullRequests(ctx context.Context, f PullRequestsFilter, fn func(PullRequest) error) error
+	ExportUsers(ctx context.Context, f UsersListFilter, fn func(User) error) error
+	ExportTeams(ctx context.Context, root string, fn func(TeamExport) error) error
 }
 
 type service struct {
 	storage Storage
 	log     *slog.Logger
+	metrics Metrics
+	cfg     Config
+
+	// rand выдаёт зёрна подбора ревьюверов, по одному на PR; previewRand —
+	// зёрна предпросмотров, чтобы они не сдвигали зёрна настоящих PR
+	randMu      sync.Mutex
+	rand        *rand.Rand
+	previewRand *rand.Rand
 }
 
-func NewService(storage Storage, log *slog.Logger) Service {
-	return &service{storage: storage, log: log}
+// Config — настройки подбора и сроков ревью.
+type Config struct {
+	// ReviewSLA — срок ревью в рабочем времени ревьювера; 0 — сроки не считаются
+	ReviewSLA time.Duration
+	// Rand — источник зёрен подбора ревьюверов; nil — случайно засеянный.
+	// С фиксированным источником назначения воспроизводимы
+	Rand *rand.Rand
+}
+
+// NewService создаёт сервис. metrics может быть nil.
+func NewService(storage Storage, log *slog.Logger, metrics Metrics, cfg Config) Service {
+	if metrics == nil {
+		metrics = nopMetrics{}
+	}
+	rng := cfg.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return &service{
+		storage:     storage,
+		log:         log,
+		metrics:     metrics,
+		cfg:         cfg,
+		rand:        rng,
+		previewRand: rand.New(rand.NewPCG(rng.Uint64(), rng.Uint64())),
+	}
 }
 
 func (s *service) PullRequestCreate(ctx context.Context, pr PullRequest) (PullRequest, error) {
 	const op = "service.pullRequest.Create"
 
-	teamName, err := s.storage.GetAuthorTeam(pr.AuthorId)
-	if err != nil {
-		return PullRequest{}, fmt.Errorf("%s: %w", op, err)
-	}
-	s.log.Info("author team", slog.String("TEAM NAME", teamName))
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
 
-	freeUsers, err := s.storage.GetFreeReviewers(teamName, pr.AuthorId)
+	a, err := s.assignReviewers(ctx, pr, s.nextSeed(s.rand), time.Now(), nil)
+	teamName := a.explanation.TeamName
 	if err != nil {
+		s.noCandidate(ctx, teamName, err)
 		return PullRequest{}, fmt.Errorf("%s: %w", op, err)
 	}
-
-	const maxReviewers = 2
-	reviewers := make([]string, 0, maxReviewers)
-	if len(freeUsers) > maxReviewers {
-		rand.Shuffle(len(freeUsers), func(i, j int) {
-			freeUsers[i], freeUsers[j] = freeUsers[j], freeUsers[i]
-		})
-		freeUsers = freeUsers[:maxReviewers]
-	}
-	for i, user := range freeUsers {
-		if i >= maxReviewers {
-			break
-		}
-		reviewers = append(reviewers, user.UserId)
-
-		s.log.Info("reviewer selected", slog.String("USER ID", user.UserId), slog.String("USERNAME", user.Username))
+	if a.explanation.PoolTeam != teamName {
+		s.metrics.Escalated(auth.OrgID(ctx), teamName)
 	}
-
-	if len(reviewers) == 0 {
-		return PullRequest{}, fmt.Errorf("%s: no available reviewers in team", op)
+	for _, p := range a.explanation.Picks {
+		s.log.Info("reviewer selected", slog.String("USER ID", p.UserId), slog.String("reason", string(p.Reason)))
 	}
 
 	newPullRequest := PullRequest{
-		AssignedReviewers: reviewers,
+		AssignedReviewers: a.reviewers,
 		AuthorId:          pr.AuthorId,
 		CreatedAt:         pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
 		PullRequestId:     pr.PullRequestId,
 		PullRequestName:   pr.PullRequestName,
 		Status:            pr.Status,
+		Version:           InitialVersion,
+		TeamName:          teamName,
+		Labels:            pr.Labels,
 	}
 
-	if err := s.storage.PullRequestCreate(newPullRequest); err != nil {
+	if err := s.storage.PullRequestCreate(ctx, newPullRequest, a.explanation); err != nil {
 		return PullRequest{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	s.log.Info("pull request created", slog.String("pr_id", pr.PullRequestId), slog.Int("reviewers_count", len(reviewers)))
+	if len(a.uncovered) > 0 {
+		newPullRequest.Warnings = skillWarnings(a.uncovered)
+		s.log.Warn("required skills not covered", slog.Any("skills", a.uncovered))
+	}
+
+	s.metrics.PullRequestCreated()
+	s.log.Info("pull request created", slog.String("pr_id", pr.PullRequestId), slog.Int("reviewers_count", len(a.reviewers)), slog.Int64("seed", a.explanation.Seed))
 
 	return newPullRequest, nil
 }
-func (s *service) PullRequestMerge(ctx context.Context, id string) (PullRequest, error) {
+// reviewTeam выбирает команду, из которой назначаются ревьюверы: указанную
+// в запросе (автор должен в ней состоять) или основную команду автора.
+func (s *service) reviewTeam(ctx context.Context, pr PullRequest) (string, error) {
+	if pr.TeamName == "" {
+		return s.storage.GetAuthorTeam(ctx, pr.AuthorId)
+	}
+
+	teams, err := s.storage.UserTeams(ctx, pr.AuthorId)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(teams, pr.TeamName) {
+		return "", ErrNotTeamMember
+	}
+	return pr.TeamName, nil
+}
+
+// escalate ищет ревьюверов в родительских командах, от ближайшей к корню,
+// и возвращает их вместе с командой, где они нашлись. PR остаётся за
+// исходной командой. nil без ошибки — выше тоже никого нет; если выше
+// кандидаты есть, но все заняты, возвращается ErrAllAtCapacity.
+func (s *service) escalate(ctx context.Context, teamName, authorID string) ([]User, string, error) {
+	ancestors, err := s.storage.TeamAncestors(ctx, teamName)
+	if err != nil {
+		return nil, "", err
+	}
+	var full error
+	for _, parent := range ancestors {
+		users, err := s.storage.GetFreeReviewers(ctx, parent, authorID)
+		if errors.Is(err, ErrAllAtCapacity) {
+			full = err
+			continue
+		}
+		if errors.Is(err, ErrNoCandidate) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		s.log.Info("reviewers escalated", slog.String("team", teamName), slog.String("parent_team", parent))
+		return users, parent, nil
+	}
+	return nil, "", full
+}
+
+// noReviewers — назначить некого: активных кандидатов нет или все заняты.
+func noReviewers(err error) bool {
+	return errors.Is(err, ErrNoCandidate) || errors.Is(err, ErrAllAtCapacity)
+}
+
+// noCandidate считает в метриках PR, которому не нашлось ревьювера, отделяя
+// исчерпанный лимит открытых ревью от отсутствия кандидатов.
+func (s *service) noCandidate(ctx context.Context, team string, err error) {
+	switch {
+	case errors.Is(err, ErrAllAtCapacity):
+		s.metrics.NoCandidate(auth.OrgID(ctx), team, ReasonAllAtCapacity)
+	case errors.Is(err, ErrNoCandidate):
+		s.metrics.NoCandidate(auth.OrgID(ctx), team, ReasonNoCandidate)
+	}
+}
+
+func (s *service) PullRequestMerge(ctx context.Context, id string, expectedVersion int64) (PullRequest, error) {
 	const op = "service.pullRrquest.Merge"
 
-	pr, err := s.storage.PullRequestMerge(id)
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	pr, merged, err := s.storage.PullRequestMerge(ctx, id, expectedVersion)
 	if err != nil {
 		return PullRequest{}, fmt.Errorf("%s: %w", op, err)
 	}
+	if merged {
+		s.metrics.PullRequestMerged()
+	}
 	return pr, nil
 }
 
 func (s *service) PullRequestReassign(ctx context.Context, r PostPullRequestReassign) (PullRequest, error) {
 	const op = "service.pull_request.Reassign"
 
-	prResp, err := s.storage.PullRequestReassign(r)
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	prResp, err := s.storage.PullRequestReassign(ctx, r)
 	if err != nil {
+		if noReviewers(err) {
+			// кандидатов ищут в команде PR
+			if pullRequest, getErr := s.storage.PullRequestGet(ctx, r.PullRequestId); getErr == nil {
+				s.noCandidate(ctx, pullRequest.TeamName, err)
+			}
+		}
 		return PullRequest{}, fmt.Errorf("%s: %w", op, err)
 	}
+	s.metrics.PullRequestReassigned()
 
 	return prResp, nil
 }
@@ -103,7 +240,10 @@ func (s *service) PullRequestReassign(ctx context.Context, r PostPullRequestReas
 func (s *service) TeamAdd(ctx context.Context, r Team) (Team, error) {
 	const op = "service.TeamAdd"
 
-	team, err := s.storage.TeamAdd(r)
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	team, err := s.storage.TeamAdd(ctx, r)
 	if err != nil {
 		return Team{}, fmt.Errorf("%s: %w", op, err)
 	}
@@ -113,7 +253,10 @@ func (s *service) TeamAdd(ctx context.Context, r Team) (Team, error) {
 func (s *service) TeamGet(ctx context.Context, r TeamName) (Team, error) {
 	const op = "service.TeamGet"
 
-	team, err := s.storage.TeamGet(r.TeamName)
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	team, err := s.storage.TeamGet(ctx, r.TeamName)
 	if err != nil {
 		return Team{}, fmt.Errorf("%s: %w", op, err)
 	}
@@ -122,20 +265,347 @@ func (s *service) TeamGet(ctx context.Context, r TeamName) (Team, error) {
 func (s *service) GetUsersReview(ctx context.Context, p GetReviewParams) ([]PullRequest, error) {
 	const op = "service.GetUsersReview"
 
-	team, err := s.storage.UsersGetReview(p.UserId)
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	team, err := s.storage.UsersGetReview(ctx, p.UserId)
 	if err != nil {
 		return []PullRequest{}, fmt.Errorf("%s: %w", op, err)
 	}
+	if err := s.reviewDeadlines(ctx, p.UserId, team); err != nil {
+		return []PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
 	return team, err
 }
 
+// reviewDeadlines заполняет ReviewDueAt у открытых PR: ReviewSLA рабочего
+// времени ревьювера от момента назначения.
+func (s *service) reviewDeadlines(ctx context.Context, reviewerID string, prs []PullRequest) error {
+	if s.cfg.ReviewSLA <= 0 || !slices.ContainsFunc(prs, func(pr PullRequest) bool {
+		return pr.Status == "OPEN" && pr.ReviewAssignedAt != nil
+	}) {
+		return nil
+	}
+	reviewer, err := s.storage.UserGet(ctx, reviewerID)
+	if err != nil {
+		return err
+	}
+	for i := range prs {
+		if prs[i].Status != "OPEN" || prs[i].ReviewAssignedAt == nil {
+			continue
+		}
+		due := reviewer.WorkingHours.AddWorkTime(*prs[i].ReviewAssignedAt, s.cfg.ReviewSLA)
+		prs[i].ReviewDueAt = &due
+	}
+	return nil
+}
+
 
 func (s *service)UsersSetIsActive(ctx context.Context, u UsersSetIsActive) (error) {
 	const op = "service.SetIsActive"
 
-	err := s.storage.UsersSetIsActive(u.UserId)
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	err := s.storage.UsersSetIsActive(ctx, u.UserId, u.IsActive)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return err
 }
+
+func (s *service) TeamAddMembers(ctx context.Context, r TeamAddMembers) (TeamChangeResult, error) {
+	const op = "service.TeamAddMembers"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	res, err := s.storage.TeamAddMembers(ctx, r)
+	if err != nil {
+		return TeamChangeResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+	s.recordReassignments(res.Reassignments)
+	return res, nil
+}
+
+func (s *service) TeamSetMemberActive(ctx context.Context, r TeamSetMemberActive) (Team, error) {
+	const op = "service.TeamSetMemberActive"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	team, err := s.storage.TeamSetMemberActive(ctx, r)
+	if err != nil {
+		return Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return team, nil
+}
+
+func (s *service) TeamSetLeadRules(ctx context.Context, r TeamSetLeadRules) (Team, error) {
+	const op = "service.TeamSetLeadRules"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	team, err := s.storage.TeamSetLeadRules(ctx, r)
+	if err != nil {
+		return Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return team, nil
+}
+
+// CodeownersValidate разбирает CODEOWNERS и проверяет, что команда
+// и все владельцы существуют. Ничего не сохраняет.
+func (s *service) CodeownersValidate(ctx context.Context, r CodeownersUpload) (CodeownersReport, error) {
+	const op = "service.CodeownersValidate"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	report, err := s.checkCodeowners(ctx, r)
+	if err != nil {
+		return CodeownersReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return report, nil
+}
+
+// CodeownersUpload сохраняет CODEOWNERS команды, если в нём нет ошибок;
+// иначе возвращает отчёт вместе с ErrInvalidCodeowners.
+func (s *service) CodeownersUpload(ctx context.Context, r CodeownersUpload) (CodeownersReport, error) {
+	const op = "service.CodeownersUpload"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	report, err := s.checkCodeowners(ctx, r)
+	if err != nil {
+		return CodeownersReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(report.Errors) > 0 {
+		return report, fmt.Errorf("%s: %w", op, ErrInvalidCodeowners)
+	}
+	if err := s.storage.TeamSetCodeowners(ctx, r.TeamName, report.Rules); err != nil {
+		return CodeownersReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+	s.log.Info("codeowners uploaded", slog.String("team", r.TeamName), slog.Int("rules", len(report.Rules)))
+	return report, nil
+}
+
+func (s *service) UserSetSkills(ctx context.Context, r UserSetSkills) (User, error) {
+	const op = "service.UserSetSkills"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	user, err := s.storage.UserSetSkills(ctx, r)
+	if err != nil {
+		return User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+func (s *service) UserSetWorkingHours(ctx context.Context, r UserSetWorkingHours) (User, error) {
+	const op = "service.UserSetWorkingHours"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	if !r.WorkingHours.Valid() {
+		return User{}, fmt.Errorf("%s: %w", op, ErrInvalidWorkingHours)
+	}
+	user, err := s.storage.UserSetWorkingHours(ctx, r)
+	if err != nil {
+		return User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+func (s *service) TeamSetMaxOpenReviews(ctx context.Context, r TeamSetMaxOpenReviews) (Team, error) {
+	const op = "service.TeamSetMaxOpenReviews"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	team, err := s.storage.TeamSetMaxOpenReviews(ctx, r)
+	if err != nil {
+		return Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return team, nil
+}
+
+func (s *service) TeamSetSelectionMode(ctx context.Context, r TeamSetSelectionMode) (Team, error) {
+	const op = "service.TeamSetSelectionMode"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	team, err := s.storage.TeamSetSelectionMode(ctx, r)
+	if err != nil {
+		return Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return team, nil
+}
+
+// UserPairings — с кем и сколько раз автор встречался в ревью за PairingWindow.
+func (s *service) UserPairings(ctx context.Context, authorID string) ([]Pairing, error) {
+	const op = "service.UserPairings"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	if _, err := s.storage.UserGet(ctx, authorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	pairings, err := s.storage.UserPairings(ctx, authorID, time.Now().Add(-PairingWindow))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return pairings, nil
+}
+
+func (s *service) TeamRemoveMembers(ctx context.Context, r TeamRemoveMembers) (TeamChangeResult, error) {
+	const op = "service.TeamRemoveMembers"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	res, err := s.storage.TeamRemoveMembers(ctx, r)
+	if err != nil {
+		return TeamChangeResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+	s.recordReassignments(res.Reassignments)
+	return res, nil
+}
+
+func (s *service) TeamRename(ctx context.Context, r TeamRename) (Team, error) {
+	const op = "service.TeamRename"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	team, err := s.storage.TeamRename(ctx, r)
+	if err != nil {
+		return Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	s.log.Info("team renamed", slog.String("team", r.TeamName), slog.String("new_team", r.NewTeamName))
+	return team, nil
+}
+
+func (s *service) TeamSetParent(ctx context.Context, r TeamSetParent) (Team, error) {
+	const op = "service.TeamSetParent"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	team, err := s.storage.TeamSetParent(ctx, r)
+	if err != nil {
+		return Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	s.log.Info("team moved", slog.String("team", r.TeamName), slog.String("parent_team", r.ParentTeam))
+	return team, nil
+}
+
+func (s *service) TeamTree(ctx context.Context, root string) ([]TeamNode, error) {
+	const op = "service.TeamTree"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	nodes, err := s.storage.TeamTree(ctx, root)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return buildTeamTree(nodes), nil
+}
+
+func (s *service) TeamStats(ctx context.Context, teamName string) (TeamStats, error) {
+	const op = "service.TeamStats"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	stats, err := s.storage.TeamStats(ctx, teamName)
+	if err != nil {
+		return TeamStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return stats, nil
+}
+
+func (s *service) TeamDelete(ctx context.Context, r TeamDelete) ([]Reassignment, error) {
+	const op = "service.TeamDelete"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	reassignments, err := s.storage.TeamDelete(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	s.recordReassignments(reassignments)
+	s.log.Info("team deleted", slog.String("team", r.TeamName), slog.Int("reassignments", len(reassignments)))
+	return reassignments, nil
+}
+
+func (s *service) UserGet(ctx context.Context, id string) (User, error) {
+	const op = "service.UserGet"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	user, err := s.storage.UserGet(ctx, id)
+	if err != nil {
+		return User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+func (s *service) UsersList(ctx context.Context, f UsersListFilter) (UsersPage, error) {
+	const op = "service.UsersList"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	page, err := s.storage.UsersList(ctx, f)
+	if err != nil {
+		return UsersPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return page, nil
+}
+
+func (s *service) UserUpdate(ctx context.Context, r UserUpdate) (UserChangeResult, error) {
+	const op = "service.UserUpdate"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	res, err := s.storage.UserUpdate(ctx, r)
+	if err != nil {
+		return UserChangeResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+	s.recordReassignments(res.Reassignments)
+	return res, nil
+}
+
+func (s *service) UserAnonymize(ctx context.Context, r UserAnonymize) (UserChangeResult, error) {
+	const op = "service.UserAnonymize"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	res, err := s.storage.UserAnonymize(ctx, r)
+	if err != nil {
+		return UserChangeResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+	s.recordReassignments(res.Reassignments)
+	s.log.Info("user anonymized", slog.String("user_id", r.UserId), slog.Int("reassignments", len(res.Reassignments)))
+	return res, nil
+}
+
+// recordReassignments учитывает в метриках ревьюверов, заменённых при изменении состава команд.
+func (s *service) recordReassignments(reassignments []Reassignment) {
+	for _, r := range reassignments {
+		if r.NewUserId != "" {
+			s.metrics.PullRequestReassigned()
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NormalizeSkillTag приводит тег навыка к виду, в котором он хранится.
+func NormalizeSkillTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func (l SkillLevel) rank() int {
+	switch l {
+	case SkillLevelBasic:
+		return 1
+	case SkillLevelIntermediate:
+		return 2
+	case SkillLevelExpert:
+		return 3
+	}
+	return 0
+}
+
+// coverSkills добирает ревьюверов из pool, пока есть места и непокрытые
+// навыки: каждый раз берётся кандидат, покрывающий больше оставшихся
+// навыков, при равенстве — с более высоким уровнем, затем первый по
+// порядку pool. Возвращает добавленных и навыки, которые покрыть не удалось.
+func (s *service) coverSkills(ctx context.Context, required, reviewers []string, pool []User, slots int) ([]string, []string, error) {
+	if len(required) == 0 {
+		return nil, nil, nil
+	}
+
+	ids := slices.Clone(reviewers)
+	for _, u := range pool {
+		ids = append(ids, u.UserId)
+	}
+	skills, err := s.storage.UsersSkills(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	level := func(userID, tag string) int {
+		for _, sk := range skills[userID] {
+			if sk.Tag == tag {
+				return sk.Level.rank()
+			}
+		}
+		return 0
+	}
+
+	var uncovered []string
+	for _, tag := range required {
+		if !slices.ContainsFunc(reviewers, func(id string) bool { return level(id, tag) > 0 }) {
+			uncovered = append(uncovered, tag)
+		}
+	}
+
+	var picked []string
+	for len(uncovered) > 0 && len(picked) < slots {
+		best, bestCount, bestRank := "", 0, 0
+		for _, u := range pool {
+			if slices.Contains(picked, u.UserId) {
+				continue
+			}
+			count, rank := 0, 0
+			for _, tag := range uncovered {
+				if l := level(u.UserId, tag); l > 0 {
+					count++
+					rank += l
+				}
+			}
+			if count > bestCount || (count == bestCount && count > 0 && rank > bestRank) {
+				best, bestCount, bestRank = u.UserId, count, rank
+			}
+		}
+		if best == "" {
+			break
+		}
+		picked = append(picked, best)
+		uncovered = slices.DeleteFunc(uncovered, func(tag string) bool { return level(best, tag) > 0 })
+	}
+	return picked, uncovered, nil
+}
+
+func skillWarnings(uncovered []string) []string {
+	warnings := make([]string, 0, len(uncovered))
+	for _, tag := range uncovered {
+		warnings = append(warnings, fmt.Sprintf("навык %q не покрыт ни одним ревьювером", tag))
+	}
+	return warnings
+}
@@ -1,27 +1,107 @@
 package pr
 
+import (
+	"context"
+	"time"
+)
+
 type Storage interface {
-	// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
-	PullRequestCreate(pr PullRequest) error
+	// Создать PR с назначенными ревьюверами и записать обоснование подбора в историю
+	PullRequestCreate(ctx context.Context, pr PullRequest, explanation AssignmentExplanation) error
+	// Получить id уже существующих PR из списка
+	PullRequestsExisting(ctx context.Context, ids []string) ([]string, error)
+	// Создать пакет PR в одной транзакции; уже существующие пропускаются и возвращаются
+	PullRequestBulkCreate(ctx context.Context, items []BulkCreateItem) (existing []string, err error)
 	// Установить флаг активности пользователя
-	UsersSetIsActive(id string) error
-	//Полуить команду автора
-	GetAuthorTeam(id string) (string, error)
-	//Получить свободных ревьеров
-	GetFreeReviewers(team string, authorid string) ([]User, error)
-	// // Пометить PR как MERGED (идемпотентная операция)
-	PullRequestMerge(id string) (PullRequest, error)
+	UsersSetIsActive(ctx context.Context, id string, isActive bool) error
+	// Получить основную команду пользователя (или первую из его команд, если основной нет)
+	GetAuthorTeam(ctx context.Context, id string) (string, error)
+	// Получить все команды пользователя
+	UserTeams(ctx context.Context, id string) ([]string, error)
+	// Получить активных участников команды, кроме автора, не исчерпавших лимит открытых ревью
+	GetFreeReviewers(ctx context.Context, team string, authorid string) ([]User, error)
+	// Сколько ещё открытых ревью можно назначить участникам с лимитом, личным
+	// или команды team; участников без лимита в ответе нет
+	OpenReviewsLeft(ctx context.Context, team string, ids []string) (map[string]int, error)
+	// Получить PR с ревьюверами
+	PullRequestGet(ctx context.Context, id string) (PullRequest, error)
+	// // Пометить PR как MERGED (идемпотентная операция).
+	// // merged == true, только если PR был переведён в MERGED этим вызовом
+	// PullRequestMerge при expectedVersion != 0 мержит PR, только если его версия совпадает.
+	PullRequestMerge(ctx context.Context, id string, expectedVersion int64) (pr PullRequest, merged bool, err error)
 	// // Переназначить конкретного ревьювера на другого из его команды)
-	PullRequestReassign(r PostPullRequestReassign) (PullRequest, error)
+	PullRequestReassign(ctx context.Context, r PostPullRequestReassign) (PullRequest, error)
 	// // Создать команду с участниками (создаёт/обновляет пользователей)
-	TeamAdd(t Team) (Team, error)
+	TeamAdd(ctx context.Context, t Team) (Team, error)
 	// // Получить команду с участниками
 	// // (GET /team/get)
-	TeamGet(teamName string)(Team, error)
+	TeamGet(ctx context.Context, teamName string) (Team, error)
 	// // Получить PR'ы, где пользователь назначен ревьювером
 	// // (GET /users/getReview)
-	UsersGetReview(id string)([]PullRequest, error)
-	// // Установить флаг активности пользователя
-	// // (POST /users/setIsActive)
-	// UsersSetIsActive()
+	UsersGetReview(ctx context.Context, id string) ([]PullRequest, error)
+	// Добавить участников в существующую команду (создаёт/обновляет/переводит пользователей)
+	TeamAddMembers(ctx context.Context, r TeamAddMembers) (TeamChangeResult, error)
+	// Получить цепочку родительских команд, от ближайшей к корню
+	TeamAncestors(ctx context.Context, teamName string) ([]string, error)
+	// Перенести команду в иерархии
+	TeamSetParent(ctx context.Context, r TeamSetParent) (Team, error)
+	// Получить команды поддерева (или всех деревьев, если root пустой) плоским списком
+	TeamTree(ctx context.Context, root string) ([]TeamNode, error)
+	// Получить показатели поддерева команды
+	TeamStats(ctx context.Context, teamName string) (TeamStats, error)
+	// Получить активных лидов команды с их правилами; лиды с исчерпанным лимитом не возвращаются
+	TeamLeadReviewers(ctx context.Context, teamName string) ([]LeadReviewer, error)
+	// Заменить правила лида команды
+	TeamSetLeadRules(ctx context.Context, r TeamSetLeadRules) (Team, error)
+	// Получить правила CODEOWNERS команды в порядке файла
+	TeamCodeowners(ctx context.Context, teamName string) ([]CodeownersRule, error)
+	// Заменить правила CODEOWNERS команды
+	TeamSetCodeowners(ctx context.Context, teamName string, rules []CodeownersRule) error
+	// Найти среди владельцев несуществующих пользователей и команды
+	UnknownOwners(ctx context.Context, users, teams []string) (unknownUsers, unknownTeams []string, err error)
+	// Получить доступных владельцев: активных пользователей и активных участников команд, кроме автора;
+	// владельцы с исчерпанным лимитом открытых ревью возвращаются отдельно
+	AvailableOwners(ctx context.Context, teamName string, users, teams []string, authorID string) (available, atCapacity []string, err error)
+	// Получить настройки подбора ревьюверов команды
+	TeamSelection(ctx context.Context, teamName string) (TeamSelection, error)
+	// Изменить режим подбора ревьюверов команды
+	TeamSetSelectionMode(ctx context.Context, r TeamSetSelectionMode) (Team, error)
+	// Изменить лимит открытых ревью участников команды по умолчанию
+	TeamSetMaxOpenReviews(ctx context.Context, r TeamSetMaxOpenReviews) (Team, error)
+	// Задать рабочее время пользователя
+	UserSetWorkingHours(ctx context.Context, r UserSetWorkingHours) (User, error)
+	// Получить моменты назначений ревьюверов на PR автора начиная с since
+	PairHistory(ctx context.Context, authorID string, reviewerIDs []string, since time.Time) (map[string][]time.Time, error)
+	// Получить участников команды, не подходящих в ревьюверы, с причинами
+	ReviewerExclusions(ctx context.Context, teamName, authorID string) ([]AssignmentExclusion, error)
+	// Получить обоснование подбора ревьюверов PR и последующие замены
+	AssignmentExplanation(ctx context.Context, prID string) (AssignmentExplanation, error)
+	// Получить пары автора с ревьюверами начиная с since, частые первыми
+	UserPairings(ctx context.Context, authorID string, since time.Time) ([]Pairing, error)
+	// Включить/выключить участие пользователя в ревью команды
+	TeamSetMemberActive(ctx context.Context, r TeamSetMemberActive) (Team, error)
+	// Исключить участников из команды
+	TeamRemoveMembers(ctx context.Context, r TeamRemoveMembers) (TeamChangeResult, error)
+	// Переименовать команду вместе с users.team_name
+	TeamRename(ctx context.Context, r TeamRename) (Team, error)
+	// Удалить команду; участники остаются без команды
+	TeamDelete(ctx context.Context, r TeamDelete) ([]Reassignment, error)
+	// Получить пользователя
+	UserGet(ctx context.Context, id string) (User, error)
+	// Список пользователей с фильтрами, постранично по user_id
+	UsersList(ctx context.Context, f UsersListFilter) (UsersPage, error)
+	// Изменить имя и/или команду пользователя
+	UserUpdate(ctx context.Context, r UserUpdate) (UserChangeResult, error)
+	// Заменить навыки пользователя
+	UserSetSkills(ctx context.Context, r UserSetSkills) (User, error)
+	// Получить навыки пользователей по user_id
+	UsersSkills(ctx context.Context, ids []string) (map[string][]Skill, error)
+	// Выгрузить PR по фильтрам, передавая их в fn по одному по мере чтения
+	ExportPullRequests(ctx context.Context, f PullRequestsFilter, fn func(PullRequest) error) error
+	// Выгрузить пользователей по фильтрам списка, передавая их в fn по одному
+	ExportUsers(ctx context.Context, f UsersListFilter, fn func(User) error) error
+	// Выгрузить команды поддерева (или все, если root пустой), передавая их в fn по одной
+	ExportTeams(ctx context.Context, root string, fn func(TeamExport) error) error
+	// Стереть персональные данные пользователя, сохранив user_id
+	UserAnonymize(ctx context.Context, r UserAnonymize) (UserChangeResult, error)
 }
@@ -0,0 +1,35 @@
+package pr
+
+// buildTeamTree собирает вложенное дерево из плоского списка команд.
+// Корнями считаются команды, родителя которых нет в списке.
+func buildTeamTree(nodes []TeamNode) []TeamNode {
+	children := make(map[string][]TeamNode, len(nodes))
+	known := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		known[n.TeamName] = true
+	}
+
+	var roots []TeamNode
+	for _, n := range nodes {
+		if n.ParentTeam != "" && known[n.ParentTeam] {
+			children[n.ParentTeam] = append(children[n.ParentTeam], n)
+			continue
+		}
+		roots = append(roots, n)
+	}
+
+	var attach func(n TeamNode) TeamNode
+	attach = func(n TeamNode) TeamNode {
+		n.Children = make([]TeamNode, 0, len(children[n.TeamName]))
+		for _, c := range children[n.TeamName] {
+			n.Children = append(n.Children, attach(c))
+		}
+		return n
+	}
+
+	tree := make([]TeamNode, 0, len(roots))
+	for _, r := range roots {
+		tree = append(tree, attach(r))
+	}
+	return tree
+}
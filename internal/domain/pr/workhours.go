@@ -0,0 +1,101 @@
+package pr
+
+import (
+	"slices"
+	"time"
+)
+
+// maxWorkDays ограничивает перебор дней при расчёте срока: расписание
+// без подходящих дней не должно зациклить расчёт.
+const maxWorkDays = 2 * 366
+
+// WorkingHours — рабочее время пользователя в его часовом поясе.
+type WorkingHours struct {
+	// Timezone — часовой пояс IANA, например Europe/Moscow
+	Timezone string
+	// Start и End — начало и конец рабочего дня в минутах от полуночи, Start < End
+	Start int
+	End   int
+	// Days — рабочие дни недели
+	Days []time.Weekday
+}
+
+// UserSetWorkingHours задаёт рабочее время; nil WorkingHours его снимает.
+type UserSetWorkingHours struct {
+	UserId       string
+	WorkingHours *WorkingHours
+}
+
+// Valid проверяет расписание перед сохранением: хотя бы один день недели и
+// Start < End в пределах суток. nil — расписания нет, это допустимо.
+func (w *WorkingHours) Valid() bool {
+	if w == nil {
+		return true
+	}
+	if len(w.Days) == 0 || w.Start < 0 || w.Start >= w.End || w.End > 24*60 {
+		return false
+	}
+	for _, d := range w.Days {
+		if d < time.Sunday || d > time.Saturday {
+			return false
+		}
+	}
+	return true
+}
+
+// unscheduled — расписание не ограничивает время: его нет или оно
+// некорректно (сохранено до проверки Valid). WorkingAt и AddWorkTime
+// трактуют такое расписание одинаково.
+func (w *WorkingHours) unscheduled() bool {
+	return w == nil || len(w.Days) == 0 || w.Start >= w.End
+}
+
+func (w *WorkingHours) location() *time.Location {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// WorkingAt сообщает, рабочее ли у пользователя время в момент t.
+// Без расписания пользователь считается доступным всегда.
+func (w *WorkingHours) WorkingAt(t time.Time) bool {
+	if w.unscheduled() {
+		return true
+	}
+	t = t.In(w.location())
+	if !slices.Contains(w.Days, t.Weekday()) {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	return minute >= w.Start && minute < w.End
+}
+
+// AddWorkTime отсчитывает d рабочего времени от момента from: нерабочие
+// часы и дни пропускаются. Без расписания d отсчитывается подряд.
+func (w *WorkingHours) AddWorkTime(from time.Time, d time.Duration) time.Time {
+	if w.unscheduled() {
+		return from.Add(d)
+	}
+	loc := w.location()
+	t := from.In(loc)
+	for range maxWorkDays {
+		y, m, day := t.Date()
+		// time.Date нормализует End = 1440 в полночь следующего дня
+		start := time.Date(y, m, day, 0, w.Start, 0, 0, loc)
+		end := time.Date(y, m, day, 0, w.End, 0, 0, loc)
+		if slices.Contains(w.Days, t.Weekday()) && t.Before(end) {
+			if t.Before(start) {
+				t = start
+			}
+			left := end.Sub(t)
+			if d <= left {
+				return t.Add(d)
+			}
+			d -= left
+		}
+		t = time.Date(y, m, day+1, 0, 0, 0, 0, loc)
+	}
+	return t.Add(d)
+}
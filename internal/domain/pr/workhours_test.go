@@ -0,0 +1,208 @@
+package pr
+
+import (
+	"testing"
+	"time"
+)
+
+var weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+var everyDay = []time.Weekday{
+	time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday,
+}
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load location %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestWorkingHoursAddWorkTime(t *testing.T) {
+	berlin := mustLocation(t, "Europe/Berlin")
+	office := &WorkingHours{Timezone: "UTC", Start: 9 * 60, End: 18 * 60, Days: weekdays}
+	// 01:00–04:00 по Берлину захватывает переходы на летнее и зимнее время
+	night := &WorkingHours{Timezone: "Europe/Berlin", Start: 60, End: 4 * 60, Days: everyDay}
+
+	tests := []struct {
+		name  string
+		hours *WorkingHours
+		from  time.Time
+		d     time.Duration
+		want  time.Time
+	}{
+		{
+			name:  "без расписания — подряд",
+			hours: nil,
+			from:  time.Date(2026, 10, 17, 23, 0, 0, 0, time.UTC),
+			d:     2 * time.Hour,
+			want:  time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "пустой список дней — подряд",
+			hours: &WorkingHours{Timezone: "UTC", Start: 9 * 60, End: 18 * 60},
+			from:  time.Date(2026, 10, 17, 23, 0, 0, 0, time.UTC),
+			d:     2 * time.Hour,
+			want:  time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Start не раньше End — подряд",
+			hours: &WorkingHours{Timezone: "UTC", Start: 18 * 60, End: 9 * 60, Days: weekdays},
+			from:  time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC),
+			d:     time.Hour,
+			want:  time.Date(2026, 10, 19, 21, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "внутри рабочего дня",
+			hours: office,
+			from:  time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC),
+			d:     3 * time.Hour,
+			want:  time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "ровно до конца дня",
+			hours: office,
+			from:  time.Date(2026, 10, 19, 16, 0, 0, 0, time.UTC),
+			d:     2 * time.Hour,
+			want:  time.Date(2026, 10, 19, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "до начала дня",
+			hours: office,
+			from:  time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC),
+			d:     time.Hour,
+			want:  time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "после конца дня",
+			hours: office,
+			from:  time.Date(2026, 10, 20, 19, 0, 0, 0, time.UTC),
+			d:     time.Hour,
+			want:  time.Date(2026, 10, 21, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "пятница через выходные",
+			hours: office,
+			from:  time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC),
+			d:     2 * time.Hour,
+			want:  time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "из выходного",
+			hours: office,
+			from:  time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+			d:     30 * time.Minute,
+			want:  time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "несколько дней",
+			hours: office,
+			from:  time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+			d:     20 * time.Hour,
+			want:  time.Date(2026, 10, 21, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "часовой пояс пользователя",
+			hours: &WorkingHours{Timezone: "Europe/Moscow", Start: 9 * 60, End: 18 * 60, Days: weekdays},
+			from:  time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC), // 17:00 MSK
+			d:     2 * time.Hour,
+			want:  time.Date(2026, 10, 20, 7, 0, 0, 0, time.UTC), // 10:00 MSK
+		},
+		{
+			name:  "End = 1440 — до полуночи",
+			hours: &WorkingHours{Timezone: "UTC", Start: 22 * 60, End: 1440, Days: everyDay},
+			from:  time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC),
+			d:     time.Hour,
+			want:  time.Date(2026, 10, 20, 22, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "End = 1440 — ровно полночь",
+			hours: &WorkingHours{Timezone: "UTC", Start: 22 * 60, End: 1440, Days: everyDay},
+			from:  time.Date(2026, 10, 19, 23, 0, 0, 0, time.UTC),
+			d:     time.Hour,
+			want:  time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "переход на летнее время укорачивает день",
+			hours: night,
+			from:  time.Date(2026, 3, 29, 1, 0, 0, 0, berlin),
+			d:     2*time.Hour + 30*time.Minute,
+			want:  time.Date(2026, 3, 30, 1, 30, 0, 0, berlin),
+		},
+		{
+			name:  "переход на зимнее время удлиняет день",
+			hours: night,
+			from:  time.Date(2026, 10, 25, 1, 0, 0, 0, berlin),
+			d:     3*time.Hour + 30*time.Minute,
+			want:  time.Date(2026, 10, 25, 2, 30, 0, 0, time.UTC), // 03:30 CET
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.hours.AddWorkTime(tt.from, tt.d)
+			if !got.Equal(tt.want) {
+				t.Errorf("AddWorkTime(%s, %s) = %s, want %s", tt.from, tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkingHoursWorkingAt(t *testing.T) {
+	office := &WorkingHours{Timezone: "UTC", Start: 9 * 60, End: 18 * 60, Days: weekdays}
+	night := &WorkingHours{Timezone: "Europe/Berlin", Start: 60, End: 4 * 60, Days: everyDay}
+
+	tests := []struct {
+		name  string
+		hours *WorkingHours
+		at    time.Time
+		want  bool
+	}{
+		{"без расписания", nil, time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC), true},
+		{"пустой список дней — без расписания", &WorkingHours{Timezone: "UTC", Start: 9 * 60, End: 18 * 60}, time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC), true},
+		{"Start не раньше End — без расписания", &WorkingHours{Timezone: "UTC", Start: 18 * 60, End: 9 * 60, Days: weekdays}, time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC), true},
+		{"начало дня", office, time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), true},
+		{"конец дня не входит", office, time.Date(2026, 10, 19, 18, 0, 0, 0, time.UTC), false},
+		{"до начала дня", office, time.Date(2026, 10, 19, 8, 59, 0, 0, time.UTC), false},
+		{"выходной", office, time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC), false},
+		{"часовой пояс пользователя", &WorkingHours{Timezone: "Europe/Moscow", Start: 9 * 60, End: 18 * 60, Days: weekdays}, time.Date(2026, 10, 19, 6, 30, 0, 0, time.UTC), true},
+		{"неизвестный пояс — UTC", &WorkingHours{Timezone: "Mars/Olympus", Start: 9 * 60, End: 18 * 60, Days: weekdays}, time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC), true},
+		{"End = 1440", &WorkingHours{Timezone: "UTC", Start: 22 * 60, End: 1440, Days: everyDay}, time.Date(2026, 10, 19, 23, 59, 0, 0, time.UTC), true},
+		{"End = 1440 — после полуночи", &WorkingHours{Timezone: "UTC", Start: 22 * 60, End: 1440, Days: everyDay}, time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), false},
+		{"после перехода на летнее время", night, time.Date(2026, 3, 29, 1, 30, 0, 0, time.UTC), true},  // 03:30 CEST
+		{"после перехода на зимнее время", night, time.Date(2026, 10, 25, 2, 30, 0, 0, time.UTC), true}, // 03:30 CET
+		{"после конца дня по Берлину", night, time.Date(2026, 3, 29, 2, 0, 0, 0, time.UTC), false},      // 04:00 CEST
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.WorkingAt(tt.at); got != tt.want {
+				t.Errorf("WorkingAt(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkingHoursValid(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours *WorkingHours
+		want  bool
+	}{
+		{"без расписания", nil, true},
+		{"рабочая неделя", &WorkingHours{Timezone: "UTC", Start: 9 * 60, End: 18 * 60, Days: weekdays}, true},
+		{"End = 1440", &WorkingHours{Timezone: "UTC", Start: 22 * 60, End: 1440, Days: everyDay}, true},
+		{"пустой список дней", &WorkingHours{Timezone: "UTC", Start: 9 * 60, End: 18 * 60}, false},
+		{"Start не раньше End", &WorkingHours{Timezone: "UTC", Start: 18 * 60, End: 9 * 60, Days: weekdays}, false},
+		{"End после полуночи", &WorkingHours{Timezone: "UTC", Start: 22 * 60, End: 1441, Days: weekdays}, false},
+		{"отрицательный Start", &WorkingHours{Timezone: "UTC", Start: -1, End: 60, Days: weekdays}, false},
+		{"несуществующий день", &WorkingHours{Timezone: "UTC", Start: 9 * 60, End: 18 * 60, Days: []time.Weekday{7}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.Valid(); got != tt.want {
+				t.Errorf("Valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
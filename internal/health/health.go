@@ -0,0 +1,96 @@
+// Package health собирает проверки готовности зависимостей сервиса.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// CheckFunc проверяет одну зависимость. Details попадают в отчёт как есть,
+// ошибка помечает зависимость как недоступную.
+type CheckFunc func(ctx context.Context) (details map[string]any, err error)
+
+type Result struct {
+	Status  Status
+	Error   string
+	Details map[string]any
+}
+
+type Report struct {
+	Status Status
+	Checks map[string]Result
+}
+
+type Registry struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	names  []string
+	checks map[string]CheckFunc
+}
+
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{
+		timeout: timeout,
+		checks:  make(map[string]CheckFunc),
+	}
+}
+
+func (r *Registry) Register(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.checks[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.checks[name] = check
+}
+
+// Run выполняет все проверки параллельно, каждую — не дольше таймаута
+// реестра. Отчёт down, если недоступна хотя бы одна зависимость.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	names := append([]string(nil), r.names...)
+	checks := make([]CheckFunc, len(names))
+	for i, name := range names {
+		checks[i] = r.checks[name]
+	}
+	r.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			details, err := checks[i](ctx)
+			res := Result{Status: StatusUp, Details: details}
+			if err != nil {
+				res.Status = StatusDown
+				res.Error = err.Error()
+			}
+			results[i] = res
+		}(i)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(names))}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pr-service/internal/domain/auth"
+	dto "pr-service/internal/infrastructure/http/handlers/dto"
+	"pr-service/internal/infrastructure/http/middleware"
+	openapi "pr-service/internal/infrastructure/http/openapi"
+	"pr-service/internal/infrastructure/http/transport"
+	"pr-service/pkg/sl_logger/sl"
+	validateResp "pr-service/pkg/validator"
+
+	"github.com/go-playground/validator"
+)
+
+// Выпустить API-ключ (только admin)
+// (POST /admin/apiKeys/create)
+func (h *API) PostAdminApiKeysCreate(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.PostAdminApiKeysCreate"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	if !h.authorize(w, r, log, h.Policy.CanManageAPIKeys) {
+		return
+	}
+
+	var req dto.PostAdminApiKeysCreateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if req.OrgId != "" && !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanManageOrgAPIKeys(p, req.OrgId)
+	}) {
+		return
+	}
+
+	key, raw, err := h.Auth.CreateAPIKey(r.Context(), dto.NewAPIKeyToModel(req))
+	if err != nil {
+		if errors.Is(err, auth.ErrOrgNotFound) {
+			responseErr(w, http.StatusNotFound, "организация не найдена")
+			return
+		}
+		log.Error("failed to create api key", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusCreated, struct {
+		Key    string         `json:"key"`
+		APIKey openapi.APIKey `json:"api_key"`
+	}{Key: raw, APIKey: dto.APIKeyFromModel(key)})
+}
+
+// Список API-ключей (только admin)
+// (GET /admin/apiKeys/list)
+func (h *API) GetAdminApiKeysList(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.GetAdminApiKeysList"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	if !h.authorize(w, r, log, h.Policy.CanManageAPIKeys) {
+		return
+	}
+
+	keys, err := h.Auth.ListAPIKeys(r.Context())
+	if err != nil {
+		log.Error("failed to list api keys", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+		return
+	}
+
+	resp := make([]openapi.APIKey, 0, len(keys))
+	for _, k := range keys {
+		resp = append(resp, dto.APIKeyFromModel(k))
+	}
+	transport.WriteJSON(w, http.StatusOK, resp)
+}
+
+// Отозвать API-ключ (только admin)
+// (POST /admin/apiKeys/revoke)
+func (h *API) PostAdminApiKeysRevoke(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.PostAdminApiKeysRevoke"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	if !h.authorize(w, r, log, h.Policy.CanManageAPIKeys) {
+		return
+	}
+
+	var req dto.PostAdminApiKeysRevokeJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if err := h.Auth.RevokeAPIKey(r.Context(), req.KeyId); err != nil {
+		if errors.Is(err, auth.ErrKeyNotFound) {
+			responseErr(w, http.StatusNotFound, "ключ не найден")
+			return
+		}
+		log.Error("failed to revoke api key", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusOK, "api key revoked OK")
+}
+
+// Создать организацию (только администратор платформы)
+// (POST /admin/organizations/create)
+func (h *API) PostAdminOrganizationsCreate(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.PostAdminOrganizationsCreate"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	if !h.authorize(w, r, log, h.Policy.CanManageOrganizations) {
+		return
+	}
+
+	var req dto.PostAdminOrganizationsCreateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	org, err := h.Auth.CreateOrganization(r.Context(), dto.NewOrganizationToModel(req))
+	if err != nil {
+		if errors.Is(err, auth.ErrOrgExists) {
+			responseErr(w, http.StatusConflict, "организация уже существует")
+			return
+		}
+		log.Error("failed to create organization", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusCreated, dto.OrganizationFromModel(org))
+}
+
+// Список организаций (только администратор платформы)
+// (GET /admin/organizations/list)
+func (h *API) GetAdminOrganizationsList(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.GetAdminOrganizationsList"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	if !h.authorize(w, r, log, h.Policy.CanManageOrganizations) {
+		return
+	}
+
+	orgs, err := h.Auth.ListOrganizations(r.Context())
+	if err != nil {
+		log.Error("failed to list organizations", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+		return
+	}
+
+	resp := make([]openapi.Organization, 0, len(orgs))
+	for _, o := range orgs {
+		resp = append(resp, dto.OrganizationFromModel(o))
+	}
+	transport.WriteJSON(w, http.StatusOK, resp)
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/policy"
 	"pr-service/internal/domain/pr"
+	"pr-service/internal/health"
 	dto "pr-service/internal/infrastructure/http/handlers/dto"
 	"pr-service/internal/infrastructure/http/middleware"
 	openapi "pr-service/internal/infrastructure/http/openapi"
@@ -17,14 +20,19 @@ import (
 	"github.com/go-playground/validator"
 )
 
+// API реализует openapi.ServerInterface. Заголовок Idempotency-Key
+// обрабатывается middleware.NewMWIdempotency до вызова хендлеров.
 type API struct {
-	Log *slog.Logger
-	Svc pr.Service
+	Log    *slog.Logger
+	Svc    pr.Service
+	Auth   *auth.Service
+	Policy *policy.Policy
+	Health *health.Registry
 }
 
 // Создать PR и автоматически назначить до 2 ревьюверов из команды автора
 // (POST /pullRequest/create)
-func (h *API) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+func (h *API) PostPullRequestCreate(w http.ResponseWriter, r *http.Request, _ openapi.PostPullRequestCreateParams) {
 	const op = "handlers.PostPullRequestCreate"
 
 	h.Log = h.Log.With(
@@ -53,9 +61,14 @@ func (h *API) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr := dto.PostPullRequestMapToModel(req)
+	newPR := dto.PostPullRequestMapToModel(req)
 
-	svcPr, err := h.Svc.PullRequestCreate(r.Context(), pr)
+	svcPr, err := h.Svc.PullRequestCreate(r.Context(), newPR)
+	if errors.Is(err, postgres.ErrAllAtCapacity) {
+		h.Log.Warn("all reviewers at capacity", sl.Err(err))
+		responseCodedErr(w, http.StatusConflict, postgres.ErrAllAtCapacity)
+		return
+	}
 	if errors.Is(err, postgres.ErrNoCandidate) {
 		h.Log.Error("bad request",
 			slog.String("type", err.Error()),
@@ -64,6 +77,11 @@ func (h *API) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
 		responseErr(w, http.StatusInternalServerError, postgres.ErrNoCandidate.Error())
 		return
 	}
+	if errors.Is(err, pr.ErrNotTeamMember) {
+		h.Log.Warn("author is not a member of the team", slog.String("team", req.TeamName))
+		responseErr(w, http.StatusBadRequest, "автор не состоит в команде team_name")
+		return
+	}
 	if errors.Is(err, postgres.ErrPrExists) {
 		h.Log.Error("bad request",
 			slog.String("type", err.Error()),
@@ -87,7 +105,7 @@ func (h *API) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
 
 // Пометить PR как MERGED (идемпотентная операция)
 // (POST /pullRequest/merge)
-func (h *API) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+func (h *API) PostPullRequestMerge(w http.ResponseWriter, r *http.Request, params openapi.PostPullRequestMergeParams) {
 	const op = "handlers.PostPullRequestMerge"
 
 	h.Log = h.Log.With(
@@ -121,7 +139,23 @@ func (h *API) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	svcPr, err := h.Svc.PullRequestMerge(r.Context(), req.PullRequestId)
+	if !h.authorize(w, r, h.Log, func(p auth.Principal) error {
+		return h.Policy.CanModifyPullRequest(r.Context(), p, req.PullRequestId)
+	}) {
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(params.IfMatch)
+	if err != nil {
+		responseErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	svcPr, err := h.Svc.PullRequestMerge(r.Context(), req.PullRequestId, expectedVersion)
+	if errors.Is(err, postgres.ErrVersionMismatch) {
+		responseCodedErr(w, http.StatusPreconditionFailed, postgres.ErrVersionMismatch)
+		return
+	}
 	if errors.Is(err, postgres.ErrNotFound) {
 		h.Log.Error("bad request",
 			slog.String("type", err.Error()),
@@ -145,7 +179,7 @@ func (h *API) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
 
 // Переназначить конкретного ревьювера на другого из его команды
 // (POST /pullRequest/reassign)
-func (h *API) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+func (h *API) PostPullRequestReassign(w http.ResponseWriter, r *http.Request, params openapi.PostPullRequestReassignParams) {
 	const op = "handlers.PostPullRequestMerge"
 
 	h.Log = h.Log.With(
@@ -172,19 +206,38 @@ func (h *API) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorize(w, r, h.Log, func(p auth.Principal) error {
+		return h.Policy.CanModifyPullRequest(r.Context(), p, req.PullRequestId)
+	}) {
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(params.IfMatch)
+	if err != nil {
+		responseErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	prReassign := dto.PostPullRequestReassignToModel(req)
+	prReassign.ExpectedVersion = expectedVersion
 	updatedPR, err := h.Svc.PullRequestReassign(r.Context(), prReassign)
 
 	if err != nil {
 		switch {
 		case errors.Is(err, postgres.ErrNotFound):
 			responseErr(w, http.StatusNotFound, postgres.ErrNotFound.Error())
+		case errors.Is(err, postgres.ErrVersionMismatch):
+			responseCodedErr(w, http.StatusPreconditionFailed, postgres.ErrVersionMismatch)
+		case errors.Is(err, postgres.ErrConcurrentUpdate):
+			responseCodedErr(w, http.StatusConflict, postgres.ErrConcurrentUpdate)
 		case errors.Is(err, postgres.ErrReviewerNotInPR):
 			responseErr(w, http.StatusBadRequest, postgres.ErrReviewerNotInPR.Error())
 		case errors.Is(err, postgres.ErrNotAssigned):
 			responseErr(w, http.StatusBadRequest, postgres.ErrNotAssigned.Error())
 		case errors.Is(err, postgres.ErrAlreadyMerged):
 			responseErr(w, http.StatusBadRequest, postgres.ErrAlreadyMerged.Error())
+		case errors.Is(err, postgres.ErrAllAtCapacity):
+			responseCodedErr(w, http.StatusConflict, postgres.ErrAllAtCapacity)
 		default:
 			h.Log.Error("reassign failed", sl.Err(err))
 			responseErr(w, http.StatusInternalServerError, "failed to reassign reviewer")
@@ -201,7 +254,7 @@ func (h *API) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
 
 // Создать команду с участниками (создаёт/обновляет пользователей)
 // (POST /team/add)
-func (h *API) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+func (h *API) PostTeamAdd(w http.ResponseWriter, r *http.Request, _ openapi.PostTeamAddParams) {
 	const op = "handlers.PostTeamAdd"
 	h.Log = h.Log.With(
 		slog.String("op", op),
@@ -221,6 +274,30 @@ func (h *API) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !dto.ValidMembershipRoles(req.Members) {
+		responseErr(w, http.StatusBadRequest, "role должна быть member или lead")
+		return
+	}
+	if !dto.ValidSelectionMode(req.SelectionMode) {
+		responseErr(w, http.StatusBadRequest, "selection_mode должен быть random или spread")
+		return
+	}
+	if req.MaxOpenReviews != nil && *req.MaxOpenReviews < 1 {
+		responseErr(w, http.StatusBadRequest, "max_open_reviews должен быть не меньше 1")
+		return
+	}
+
+	if !h.authorize(w, r, h.Log, func(p auth.Principal) error {
+		if req.ParentTeam != nil && *req.ParentTeam != "" {
+			if err := h.Policy.CanManageHierarchy(p); err != nil {
+				return err
+			}
+		}
+		return h.Policy.CanManageTeam(r.Context(), p, req.TeamName)
+	}) {
+		return
+	}
+
 	teamDomain := dto.TeamMapToModel(req)
 
 	createdTeam, err := h.Svc.TeamAdd(r.Context(), teamDomain)
@@ -236,9 +313,14 @@ func (h *API) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 			responseErr(w, http.StatusBadRequest, "в команде должен быть хотя бы один участник")
 			return
 
+		case errors.Is(err, postgres.ErrUserAnonymized):
+			h.Log.Warn("anonymized user in team", sl.Err(err))
+			responseErr(w, http.StatusConflict, postgres.ErrUserAnonymized.Error())
+			return
+
 		case errors.Is(err, postgres.ErrNotFound):
 			h.Log.Warn("user not found when creating team", slog.String("team", teamDomain.TeamName))
-			responseErr(w, http.StatusNotFound, "один или несколько пользователей не найдены")
+			responseErr(w, http.StatusNotFound, "пользователь или родительская команда не найдены")
 			return
 
 		default:
@@ -281,6 +363,185 @@ func (h *API) GetTeamGet(w http.ResponseWriter, r *http.Request, params openapi.
 	teamRequestOK(w, team)
 }
 
+// Создать пакет PR
+// (POST /pullRequest/bulkCreate)
+func (h *API) PostPullRequestBulkCreate(w http.ResponseWriter, r *http.Request, _ openapi.PostPullRequestBulkCreateParams) {
+	const op = "handlers.PostPullRequestBulkCreate"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostPullRequestBulkCreateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("bad request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+		log.Warn("invalid request", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(validateErr))
+		return
+	}
+
+	if !h.authorize(w, r, log, h.Policy.CanImportPullRequests) {
+		return
+	}
+
+	// некорректные PR не доходят до сервиса, но остаются в ответе на своих местах
+	results := make([]openapi.BulkCreateResult, len(req.PullRequests))
+	prs := make([]pr.PullRequest, 0, len(req.PullRequests))
+	indexes := make([]int, 0, len(req.PullRequests))
+	for i, item := range req.PullRequests {
+		if err := validate.Struct(item); err != nil {
+			results[i] = bulkFailed(item.PullRequestId, "INVALID_ITEM", err.Error())
+			continue
+		}
+		if !dto.ValidBulkDates(item) {
+			results[i] = bulkFailed(item.PullRequestId, "INVALID_ITEM", "у MERGED обязательны created_at и merged_at не раньше него; у OPEN merged_at не задаётся")
+			continue
+		}
+		prs = append(prs, dto.BulkCreateItemToModel(item))
+		indexes = append(indexes, i)
+	}
+
+	created, err := h.Svc.PullRequestBulkCreate(r.Context(), prs)
+	if err != nil {
+		log.Error("failed to bulk create pull requests", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+		return
+	}
+	for k, res := range created {
+		i := indexes[k]
+		if res.Err != nil {
+			code, message := bulkItemError(log, res.Err)
+			results[i] = bulkFailed(res.PullRequest.PullRequestId, code, message)
+			continue
+		}
+		prResp := pullRequestFromModel(res.PullRequest)
+		results[i] = openapi.BulkCreateResult{
+			PullRequestId: res.PullRequest.PullRequestId,
+			Status:        openapi.Created,
+			Pr:            &prResp,
+		}
+	}
+
+	resp := dto.PullRequestBulkCreateResponse{Results: results}
+	for _, res := range results {
+		if res.Status == openapi.Created {
+			resp.Created++
+		} else {
+			resp.Failed++
+		}
+	}
+	log.Info("pull requests imported", slog.Int("created", resp.Created), slog.Int("failed", resp.Failed))
+	transport.WriteJSON(w, http.StatusOK, resp)
+}
+
+func bulkFailed(prID, code, message string) openapi.BulkCreateResult {
+	res := openapi.BulkCreateResult{PullRequestId: prID, Status: openapi.Failed}
+	res.Error = &struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{Code: code, Message: message}
+	return res
+}
+
+// bulkItemError переводит ошибку одного PR пакета в код и сообщение ответа.
+func bulkItemError(log *slog.Logger, err error) (string, string) {
+	switch {
+	case errors.Is(err, pr.ErrPullRequestExists):
+		return string(openapi.PREXISTS), postgres.ErrPrExists.Error()
+	case errors.Is(err, pr.ErrAllAtCapacity):
+		return string(openapi.ALLATCAPACITY), postgres.ErrAllAtCapacity.Error()
+	case errors.Is(err, pr.ErrNoCandidate):
+		return string(openapi.NOCANDIDATE), postgres.ErrNoCandidate.Error()
+	case errors.Is(err, pr.ErrNotTeamMember):
+		return "NOT_TEAM_MEMBER", "автор не состоит в команде team_name"
+	case errors.Is(err, postgres.ErrNotFound):
+		return string(openapi.NOTFOUND), "автор или команда не найдены"
+	}
+	log.Error("failed to create pull request", sl.Err(err))
+	return "INTERNAL", "внутренняя ошибка сервера"
+}
+
+// Показать, кто был бы назначен ревьюверами, не создавая PR
+// (POST /pullRequest/previewAssignment)
+func (h *API) PostPullRequestPreviewAssignment(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.PostPullRequestPreviewAssignment"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostPullRequestCreateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("bad request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+		log.Warn("invalid request", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(validateErr))
+		return
+	}
+
+	preview, err := h.Svc.PullRequestPreviewAssignment(r.Context(), dto.PostPullRequestMapToModel(req))
+	if err != nil {
+		switch {
+		case errors.Is(err, postgres.ErrAllAtCapacity):
+			log.Warn("all reviewers at capacity", sl.Err(err))
+			responseCodedErr(w, http.StatusConflict, postgres.ErrAllAtCapacity)
+		case errors.Is(err, postgres.ErrNoCandidate):
+			log.Warn("no candidate", sl.Err(err))
+			responseErr(w, http.StatusConflict, postgres.ErrNoCandidate.Error())
+		case errors.Is(err, pr.ErrNotTeamMember):
+			log.Warn("author is not a member of the team", slog.String("team", req.TeamName))
+			responseErr(w, http.StatusBadRequest, "автор не состоит в команде team_name")
+		case errors.Is(err, postgres.ErrNotFound):
+			log.Warn("author or team not found", sl.Err(err))
+			responseErr(w, http.StatusNotFound, postgres.ErrNotFound.Error())
+		default:
+			log.Error("failed to preview assignment", sl.Err(err))
+			responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+		}
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusOK, dto.AssignmentPreviewFromModel(preview))
+}
+
+// Объяснить подбор ревьюверов PR
+// (GET /pullRequest/explainAssignment)
+func (h *API) GetPullRequestExplainAssignment(w http.ResponseWriter, r *http.Request, params openapi.GetPullRequestExplainAssignmentParams) {
+	const op = "handlers.GetPullRequestExplainAssignment"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+		slog.String("pull_request_id", params.PullRequestId),
+	)
+
+	explanation, err := h.Svc.PullRequestExplainAssignment(r.Context(), params.PullRequestId)
+	if err != nil {
+		switch {
+		case errors.Is(err, postgres.ErrNotFound):
+			log.Warn("assignment history not found")
+			responseErr(w, http.StatusNotFound, "история назначений PR не найдена")
+		default:
+			log.Error("failed to explain assignment", sl.Err(err))
+			responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+		}
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusOK, dto.AssignmentExplanationFromModel(explanation))
+}
+
 // Получить PR'ы, где пользователь назначен ревьювером
 // (GET /users/getReview)
 func (h *API) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params openapi.GetUsersGetReviewParams) {
@@ -310,7 +571,7 @@ func (h *API) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params o
 
 // Установить флаг активности пользователя
 // (POST /users/setIsActive)
-func (h *API) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
+func (h *API) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request, _ openapi.PostUsersSetIsActiveParams) {
 	const op = "handlers.PostPullRequestCreate"
 
 	h.Log = h.Log.With(
@@ -339,6 +600,12 @@ func (h *API) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorize(w, r, h.Log, func(p auth.Principal) error {
+		return h.Policy.CanSetUserActive(r.Context(), p, req.UserId)
+	}) {
+		return
+	}
+
 	user := dto.UsersSetIsActiveToModel(req)
 
 	err = h.Svc.UsersSetIsActive(r.Context(), user)
@@ -350,6 +617,14 @@ func (h *API) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 		responseErr(w, http.StatusInternalServerError, postgres.ErrNoCandidate.Error())
 		return
 	}
+	if errors.Is(err, postgres.ErrUserAnonymized) {
+		responseErr(w, http.StatusConflict, postgres.ErrUserAnonymized.Error())
+		return
+	}
+	if errors.Is(err, postgres.ErrNotFound) {
+		responseErr(w, http.StatusNotFound, "пользователь не найден")
+		return
+	}
 	if errors.Is(err, postgres.ErrPrExists) {
 		h.Log.Error("bad request",
 			slog.String("type", err.Error()),
@@ -383,8 +658,25 @@ func responseErr(w http.ResponseWriter, c int, m string) {
 	transport.WriteJSON(w, c, resp)
 }
 
+// codedErr — ошибка хранилища с кодом из спецификации.
+type codedErr interface {
+	error
+	Code() openapi.ErrorResponseErrorCode
+}
+
+// responseCodedErr отвечает кодом и сообщением ошибки хранилища, а не
+// текстом HTTP-статуса: клиенты различают по коду ответы с одним статусом.
+func responseCodedErr(w http.ResponseWriter, c int, err codedErr) {
+	middleware.WriteError(w, c, err.Code(), err.Error())
+}
+
 func pullRequestOK(w http.ResponseWriter, pr pr.PullRequest) {
-	r := openapi.PullRequest{
+	setETag(w, pr.Version)
+	transport.WriteJSON(w, http.StatusOK, pullRequestFromModel(pr))
+}
+
+func pullRequestFromModel(pr pr.PullRequest) openapi.PullRequest {
+	return openapi.PullRequest{
 		AssignedReviewers: pr.AssignedReviewers,
 		CreatedAt:         pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
@@ -392,24 +684,29 @@ func pullRequestOK(w http.ResponseWriter, pr pr.PullRequest) {
 		PullRequestId:     pr.PullRequestId,
 		PullRequestName:   pr.PullRequestName,
 		Status:            openapi.PullRequestStatus(pr.Status),
+		Version:           pr.Version,
+		TeamName:          teamNameOrNil(pr.TeamName),
+		Labels:            stringsOrNil(pr.Labels),
+		Warnings:          stringsOrNil(pr.Warnings),
 	}
-	transport.WriteJSON(w, http.StatusOK, r)
 }
 
 func teamRequestOK(w http.ResponseWriter, pr pr.Team) {
-	members := make([]openapi.TeamMember, 0, len(pr.Members))
-	for _, m := range pr.Members {
-		members = append(members, openapi.TeamMember{
-			IsActive: m.IsActive,
-			UserId:   m.UserId,
-			Username: m.Username,
-		})
-	}
-	r := openapi.Team{
-		Members:  members,
-		TeamName: pr.TeamName,
-	}
-	transport.WriteJSON(w, http.StatusOK, r)
+	transport.WriteJSON(w, http.StatusOK, dto.TeamFromModel(pr))
+}
+
+func teamNameOrNil(teamName string) *string {
+	if teamName == "" {
+		return nil
+	}
+	return &teamName
+}
+
+func stringsOrNil(values []string) *[]string {
+	if len(values) == 0 {
+		return nil
+	}
+	return &values
 }
 
 func GetReviewOK(w http.ResponseWriter, reviews []pr.PullRequest) {
@@ -428,6 +725,11 @@ func GetReviewOK(w http.ResponseWriter, reviews []pr.PullRequest) {
 			CreatedAt:         pr.CreatedAt,
 			MergedAt:          pr.MergedAt,
 			AssignedReviewers: pr.AssignedReviewers,
+			Version:           pr.Version,
+			TeamName:          teamNameOrNil(pr.TeamName),
+			Labels:            stringsOrNil(pr.Labels),
+			ReviewAssignedAt:  pr.ReviewAssignedAt,
+			ReviewDueAt:       pr.ReviewDueAt,
 		}
 	}
 
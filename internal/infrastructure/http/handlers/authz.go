@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/policy"
+	"pr-service/internal/infrastructure/http/middleware"
+	openapi "pr-service/internal/infrastructure/http/openapi"
+	"pr-service/internal/infrastructure/storage/postgres"
+	"pr-service/pkg/sl_logger/sl"
+)
+
+// authorize проверяет право principal-а запроса на операцию через Policy.
+// При отказе ответ уже записан, и возвращается false.
+func (h *API) authorize(w http.ResponseWriter, r *http.Request, log *slog.Logger, check func(auth.Principal) error) bool {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		log.Error("no principal in request context")
+		middleware.WriteError(w, http.StatusUnauthorized, openapi.UNAUTHORIZED, "требуется аутентификация")
+		return false
+	}
+
+	err := check(principal)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, policy.ErrForbidden):
+		log.Warn("forbidden",
+			slog.String("subject", principal.Subject),
+			slog.String("role", string(principal.Role)),
+		)
+		middleware.WriteError(w, http.StatusForbidden, openapi.FORBIDDEN, "недостаточно прав")
+	case errors.Is(err, postgres.ErrNotFound):
+		responseErr(w, http.StatusNotFound, postgres.ErrNotFound.Error())
+	default:
+		log.Error("authorization failed", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+	}
+	return false
+}
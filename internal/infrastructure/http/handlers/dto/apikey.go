@@ -0,0 +1,45 @@
+package api_dto
+
+import (
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/infrastructure/http/openapi"
+)
+
+type PostAdminApiKeysCreateJSONBody struct {
+	Name     string `json:"name" validate:"required,min=3"`
+	Role     string `json:"role" validate:"required,oneof=admin team_lead member bot"`
+	Subject  string `json:"subject" validate:"required"`
+	TeamName string `json:"team_name"`
+	OrgId    string `json:"org_id" validate:"omitempty,max=64"`
+}
+
+type PostAdminApiKeysRevokeJSONBody struct {
+	KeyId string `json:"key_id" validate:"required"`
+}
+
+func NewAPIKeyToModel(req PostAdminApiKeysCreateJSONBody) auth.NewAPIKey {
+	return auth.NewAPIKey{
+		Name:     req.Name,
+		Role:     auth.Role(req.Role),
+		Subject:  req.Subject,
+		TeamName: req.TeamName,
+		OrgID:    req.OrgId,
+	}
+}
+
+func APIKeyFromModel(k auth.APIKey) openapi.APIKey {
+	resp := openapi.APIKey{
+		KeyId:     k.KeyID,
+		OrgId:     k.OrgID,
+		Name:      k.Name,
+		Role:      openapi.Role(k.Role),
+		Subject:   k.Subject,
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+	}
+	if k.TeamName != "" {
+		teamName := k.TeamName
+		resp.TeamName = &teamName
+	}
+	return resp
+}
@@ -0,0 +1,179 @@
+package api_dto
+
+import (
+	"pr-service/internal/domain/pr"
+	openapi "pr-service/internal/infrastructure/http/openapi"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportParams — общие параметры выгрузок; Status есть только у PR.
+type ExportParams struct {
+	Format string `validate:"oneof=csv ndjson"`
+	Status string `validate:"omitempty,oneof=OPEN MERGED"`
+}
+
+// ExportFormat — формат выгрузки, по умолчанию csv.
+func ExportFormat[T ~string](format *T) string {
+	if format == nil {
+		return "csv"
+	}
+	return string(*format)
+}
+
+// Строки выгрузок: в ndjson пишутся как есть, в csv — через CSVRecord
+// в порядке соответствующего заголовка. Списки в csv склеиваются через «;».
+
+var PullRequestExportHeader = []string{
+	"pull_request_id", "pull_request_name", "author_id", "team_name", "status",
+	"assigned_reviewers", "labels", "created_at", "merged_at",
+}
+
+type PullRequestExportRow struct {
+	PullRequestId     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorId          string     `json:"author_id"`
+	TeamName          string     `json:"team_name,omitempty"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	Labels            []string   `json:"labels"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+}
+
+func PullRequestExportFromModel(p pr.PullRequest) PullRequestExportRow {
+	return PullRequestExportRow{
+		PullRequestId:     p.PullRequestId,
+		PullRequestName:   p.PullRequestName,
+		AuthorId:          p.AuthorId,
+		TeamName:          p.TeamName,
+		Status:            p.Status,
+		AssignedReviewers: nonNil(p.AssignedReviewers),
+		Labels:            nonNil(p.Labels),
+		CreatedAt:         p.CreatedAt,
+		MergedAt:          p.MergedAt,
+	}
+}
+
+func (r PullRequestExportRow) CSVRecord() []string {
+	return []string{
+		r.PullRequestId, r.PullRequestName, r.AuthorId, r.TeamName, r.Status,
+		strings.Join(r.AssignedReviewers, ";"), strings.Join(r.Labels, ";"),
+		csvTime(r.CreatedAt), csvTime(r.MergedAt),
+	}
+}
+
+var UserExportHeader = []string{
+	"user_id", "username", "team_name", "teams", "is_active", "seniority", "skills",
+	"max_open_reviews", "timezone", "work_start", "work_end", "work_days",
+}
+
+type UserExportRow struct {
+	UserId         string                `json:"user_id"`
+	Username       string                `json:"username"`
+	TeamName       string                `json:"team_name,omitempty"`
+	Teams          []string              `json:"teams"`
+	IsActive       bool                  `json:"is_active"`
+	Seniority      string                `json:"seniority,omitempty"`
+	Skills         []openapi.Skill       `json:"skills"`
+	MaxOpenReviews int                   `json:"max_open_reviews,omitempty"`
+	WorkingHours   *openapi.WorkingHours `json:"working_hours,omitempty"`
+}
+
+func UserExportFromModel(u pr.User) UserExportRow {
+	row := UserExportRow{
+		UserId:         u.UserId,
+		Username:       u.Username,
+		TeamName:       u.TeamName,
+		Teams:          nonNil(u.Teams),
+		IsActive:       u.IsActive,
+		Seniority:      string(u.Seniority),
+		Skills:         make([]openapi.Skill, 0, len(u.Skills)),
+		MaxOpenReviews: u.MaxOpenReviews,
+		WorkingHours:   workingHoursFromModel(u.WorkingHours),
+	}
+	for _, s := range u.Skills {
+		row.Skills = append(row.Skills, openapi.Skill{Tag: s.Tag, Level: openapi.SkillLevel(s.Level)})
+	}
+	return row
+}
+
+func (r UserExportRow) CSVRecord() []string {
+	skills := make([]string, 0, len(r.Skills))
+	for _, s := range r.Skills {
+		skills = append(skills, s.Tag+":"+string(s.Level))
+	}
+	maxOpen := ""
+	if r.MaxOpenReviews > 0 {
+		maxOpen = strconv.Itoa(r.MaxOpenReviews)
+	}
+	var timezone, start, end, days string
+	if r.WorkingHours != nil {
+		timezone, start, end = r.WorkingHours.Timezone, r.WorkingHours.Start, r.WorkingHours.End
+		for i, d := range r.WorkingHours.Days {
+			if i > 0 {
+				days += ";"
+			}
+			days += strconv.Itoa(d)
+		}
+	}
+	return []string{
+		r.UserId, r.Username, r.TeamName, strings.Join(r.Teams, ";"),
+		strconv.FormatBool(r.IsActive), r.Seniority, strings.Join(skills, ";"),
+		maxOpen, timezone, start, end, days,
+	}
+}
+
+var TeamExportHeader = []string{
+	"team_name", "parent_team", "selection_mode", "max_open_reviews",
+	"prefer_working_hours", "members", "active_members",
+}
+
+type TeamExportRow struct {
+	TeamName           string `json:"team_name"`
+	ParentTeam         string `json:"parent_team,omitempty"`
+	SelectionMode      string `json:"selection_mode"`
+	MaxOpenReviews     int    `json:"max_open_reviews,omitempty"`
+	PreferWorkingHours bool   `json:"prefer_working_hours"`
+	Members            int    `json:"members"`
+	ActiveMembers      int    `json:"active_members"`
+}
+
+func TeamExportFromModel(t pr.TeamExport) TeamExportRow {
+	return TeamExportRow{
+		TeamName:           t.TeamName,
+		ParentTeam:         t.ParentTeam,
+		SelectionMode:      string(t.SelectionMode),
+		MaxOpenReviews:     t.MaxOpenReviews,
+		PreferWorkingHours: t.PreferWorkingHours,
+		Members:            t.Members,
+		ActiveMembers:      t.ActiveMembers,
+	}
+}
+
+func (r TeamExportRow) CSVRecord() []string {
+	maxOpen := ""
+	if r.MaxOpenReviews > 0 {
+		maxOpen = strconv.Itoa(r.MaxOpenReviews)
+	}
+	return []string{
+		r.TeamName, r.ParentTeam, r.SelectionMode, maxOpen,
+		strconv.FormatBool(r.PreferWorkingHours),
+		strconv.Itoa(r.Members), strconv.Itoa(r.ActiveMembers),
+	}
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
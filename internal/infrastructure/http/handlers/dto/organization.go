@@ -0,0 +1,26 @@
+package api_dto
+
+import (
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/infrastructure/http/openapi"
+)
+
+type PostAdminOrganizationsCreateJSONBody struct {
+	OrgId string `json:"org_id" validate:"required,max=64"`
+	Name  string `json:"name" validate:"required"`
+}
+
+func NewOrganizationToModel(req PostAdminOrganizationsCreateJSONBody) auth.Organization {
+	return auth.Organization{
+		OrgID: req.OrgId,
+		Name:  req.Name,
+	}
+}
+
+func OrganizationFromModel(o auth.Organization) openapi.Organization {
+	return openapi.Organization{
+		OrgId:     o.OrgID,
+		Name:      o.Name,
+		CreatedAt: o.CreatedAt,
+	}
+}
@@ -3,15 +3,20 @@ package api_dto
 import (
 	"pr-service/internal/domain/pr"
 	"pr-service/internal/infrastructure/http/openapi"
+	"slices"
 	"time"
 )
 
 var now = time.Now()
 
 type PostPullRequestCreateJSONBody struct {
-	AuthorId        string `json:"author_id" validate:"required"`
-	PullRequestId   string `json:"pull_request_id" validate:"required"`
-	PullRequestName string `json:"pull_request_name" validate:"required,min=3"`
+	AuthorId        string   `json:"author_id" validate:"required"`
+	PullRequestId   string   `json:"pull_request_id" validate:"required"`
+	PullRequestName string   `json:"pull_request_name" validate:"required,min=3"`
+	TeamName        string   `json:"team_name"`
+	Labels          []string `json:"labels" validate:"omitempty,dive,required"`
+	ChangedFiles    []string `json:"changed_files" validate:"omitempty,dive,required"`
+	RequiredSkills  []string `json:"required_skills" validate:"omitempty,dive,required"`
 }
 
 type PostPullRequestMergeJSONBody struct {
@@ -53,9 +58,25 @@ func PostPullRequestMapToModel(req PostPullRequestCreateJSONBody) pr.PullRequest
 		PullRequestId:     req.PullRequestId,
 		PullRequestName:   req.PullRequestName,
 		Status:            "OPEN",
+		TeamName:          req.TeamName,
+		Labels:            req.Labels,
+		ChangedFiles:      req.ChangedFiles,
+		RequiredSkills:    requiredSkillsToModel(req.RequiredSkills),
 	}
 }
 
+// requiredSkillsToModel нормализует теги и убирает повторы.
+func requiredSkillsToModel(tags []string) []string {
+	var skills []string
+	for _, tag := range tags {
+		tag = pr.NormalizeSkillTag(tag)
+		if tag != "" && !slices.Contains(skills, tag) {
+			skills = append(skills, tag)
+		}
+	}
+	return skills
+}
+
 func TeamMapToModel(req openapi.Team) pr.Team {
 	members := make([]pr.TeamMember, 0, len(req.Members))
 	for _, m := range req.Members {
@@ -63,10 +84,127 @@ func TeamMapToModel(req openapi.Team) pr.Team {
 			IsActive: m.IsActive,
 			UserId:   m.UserId,
 			Username: m.Username,
+			Role:     MembershipRoleToModel(m.Role),
 		})
 	}
-	return pr.Team{
+	team := pr.Team{
 		Members:  members,
 		TeamName: req.TeamName,
 	}
+	if req.ParentTeam != nil {
+		team.ParentTeam = *req.ParentTeam
+	}
+	if req.SelectionMode != nil {
+		team.SelectionMode = pr.SelectionMode(*req.SelectionMode)
+	}
+	if req.MaxOpenReviews != nil {
+		team.MaxOpenReviews = *req.MaxOpenReviews
+	}
+	if req.PreferWorkingHours != nil {
+		team.PreferWorkingHours = *req.PreferWorkingHours
+	}
+	return team
+}
+
+func AssignmentExplanationFromModel(e pr.AssignmentExplanation) openapi.AssignmentExplanation {
+	resp := openapi.AssignmentExplanation{
+		PullRequestId:      e.PullRequestId,
+		TeamName:           e.TeamName,
+		PoolTeam:           e.PoolTeam,
+		Seed:               e.Seed,
+		AssignedAt:         e.AssignedAt,
+		SelectionMode:      openapi.SelectionMode(e.SelectionMode),
+		PreferWorkingHours: e.PreferWorkingHours,
+		Candidates:         append([]string{}, e.Candidates...),
+		Exclusions:         make([]openapi.AssignmentExclusion, 0, len(e.Exclusions)),
+		Picks:              make([]openapi.AssignmentPick, 0, len(e.Picks)),
+		CreatedAt:          e.CreatedAt,
+		Changes:            make([]openapi.ReviewerChange, 0, len(e.Changes)),
+	}
+	for _, x := range e.Exclusions {
+		resp.Exclusions = append(resp.Exclusions, openapi.AssignmentExclusion{
+			UserId:   x.UserId,
+			TeamName: x.TeamName,
+			Reason:   openapi.AssignmentExclusionReason(x.Reason),
+		})
+	}
+	for _, p := range e.Picks {
+		resp.Picks = append(resp.Picks, openapi.AssignmentPick{
+			UserId: p.UserId,
+			Reason: openapi.AssignmentPickReason(p.Reason),
+		})
+	}
+	for _, c := range e.Changes {
+		change := openapi.ReviewerChange{
+			Event:     openapi.ReviewerChangeEvent(c.Event),
+			OldUserId: c.OldUserId,
+			At:        c.At,
+		}
+		if c.NewUserId != "" {
+			change.NewUserId = &c.NewUserId
+		}
+		resp.Changes = append(resp.Changes, change)
+	}
+	return resp
+}
+
+func AssignmentPreviewFromModel(p pr.AssignmentPreview) openapi.AssignmentPreview {
+	e := AssignmentExplanationFromModel(p.AssignmentExplanation)
+	resp := openapi.AssignmentPreview{
+		TeamName:           e.TeamName,
+		PoolTeam:           e.PoolTeam,
+		SelectionMode:      e.SelectionMode,
+		PreferWorkingHours: e.PreferWorkingHours,
+		Candidates:         e.Candidates,
+		Exclusions:         e.Exclusions,
+		Picks:              e.Picks,
+	}
+	if len(p.Warnings) > 0 {
+		resp.Warnings = &p.Warnings
+	}
+	return resp
+}
+
+// PostPullRequestBulkCreateJSONBody — max совпадает с pr.MaxBulkCreate.
+type PostPullRequestBulkCreateJSONBody struct {
+	PullRequests []BulkCreatePullRequestBody `json:"pull_requests" validate:"required,min=1,max=1000"`
+}
+
+type BulkCreatePullRequestBody struct {
+	PostPullRequestCreateJSONBody
+	Status    string     `json:"status" validate:"omitempty,oneof=OPEN MERGED"`
+	CreatedAt *time.Time `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+}
+
+type PullRequestBulkCreateResponse struct {
+	Created int                        `json:"created"`
+	Failed  int                        `json:"failed"`
+	Results []openapi.BulkCreateResult `json:"results"`
+}
+
+// ValidBulkDates: merged_at задаётся только у MERGED; у него обязательны
+// created_at и merged_at, и merged_at не раньше created_at.
+func ValidBulkDates(item BulkCreatePullRequestBody) bool {
+	if item.Status != "MERGED" {
+		return item.MergedAt == nil
+	}
+	if item.CreatedAt == nil || item.MergedAt == nil {
+		return false
+	}
+	return !item.MergedAt.Before(*item.CreatedAt)
+}
+
+func BulkCreateItemToModel(item BulkCreatePullRequestBody) pr.PullRequest {
+	p := PostPullRequestMapToModel(item.PostPullRequestCreateJSONBody)
+	createdAt := time.Now()
+	if item.CreatedAt != nil {
+		createdAt = *item.CreatedAt
+	}
+	p.CreatedAt = &createdAt
+	if item.Status == "MERGED" {
+		p.Status = "MERGED"
+		p.MergedAt = item.MergedAt
+	}
+	return p
 }
@@ -0,0 +1,367 @@
+package api_dto
+
+import (
+	"pr-service/internal/domain/pr"
+	"pr-service/internal/infrastructure/http/openapi"
+)
+
+type TeamMemberBody struct {
+	UserId   string `json:"user_id" validate:"required"`
+	Username string `json:"username" validate:"required"`
+	IsActive bool   `json:"is_active"`
+	Role     string `json:"role" validate:"omitempty,oneof=member lead"`
+}
+
+type PostTeamAddMembersJSONBody struct {
+	TeamName string           `json:"team_name" validate:"required"`
+	Members  []TeamMemberBody `json:"members" validate:"required,min=1,dive"`
+	// OpenReviews не используется: добавление в команду не исключает из других
+	OpenReviews string `json:"open_reviews" validate:"omitempty,oneof=keep reassign"`
+}
+
+type PostTeamSetMemberActiveJSONBody struct {
+	TeamName string `json:"team_name" validate:"required"`
+	UserId   string `json:"user_id" validate:"required"`
+	IsActive *bool  `json:"is_active" validate:"required"`
+}
+
+type LeadRuleBody struct {
+	Kind  string `json:"kind" validate:"required,oneof=name_prefix label author_seniority fallback"`
+	Value string `json:"value"`
+}
+
+type PostTeamSetLeadRulesJSONBody struct {
+	TeamName string         `json:"team_name" validate:"required"`
+	UserId   string         `json:"user_id" validate:"required"`
+	Rules    []LeadRuleBody `json:"rules" validate:"required,dive"`
+}
+
+type PostTeamCodeownersJSONBody struct {
+	TeamName string `json:"team_name" validate:"required"`
+	Content  string `json:"content"`
+}
+
+type PostTeamSetMaxOpenReviewsJSONBody struct {
+	TeamName       string `json:"team_name" validate:"required"`
+	MaxOpenReviews *int   `json:"max_open_reviews" validate:"required,min=0"`
+}
+
+type PostTeamSetSelectionModeJSONBody struct {
+	TeamName           string `json:"team_name" validate:"required"`
+	SelectionMode      string `json:"selection_mode" validate:"omitempty,oneof=random spread"`
+	PreferWorkingHours *bool  `json:"prefer_working_hours"`
+}
+
+type PostTeamRemoveMembersJSONBody struct {
+	TeamName    string   `json:"team_name" validate:"required"`
+	UserIds     []string `json:"user_ids" validate:"required,min=1,dive,required"`
+	OpenReviews string   `json:"open_reviews" validate:"required,oneof=keep reassign"`
+}
+
+type PostTeamRenameJSONBody struct {
+	TeamName    string `json:"team_name" validate:"required"`
+	NewTeamName string `json:"new_team_name" validate:"required,nefield=TeamName"`
+}
+
+type PostTeamSetParentJSONBody struct {
+	TeamName   string  `json:"team_name" validate:"required"`
+	ParentTeam *string `json:"parent_team"`
+}
+
+type TeamTreeResponse struct {
+	Teams []openapi.TeamTreeNode `json:"teams"`
+}
+
+type TeamStatsResponse struct {
+	Total openapi.TeamStatsRow   `json:"total"`
+	Teams []openapi.TeamStatsRow `json:"teams"`
+}
+
+type DeleteTeamParams struct {
+	TeamName    string `validate:"required"`
+	OpenReviews string `validate:"required,oneof=keep reassign"`
+}
+
+func TeamAddMembersToModel(req PostTeamAddMembersJSONBody) pr.TeamAddMembers {
+	members := make([]pr.TeamMember, 0, len(req.Members))
+	for _, m := range req.Members {
+		members = append(members, pr.TeamMember{
+			IsActive: m.IsActive,
+			UserId:   m.UserId,
+			Username: m.Username,
+			Role:     pr.MembershipRole(m.Role),
+		})
+	}
+	return pr.TeamAddMembers{
+		TeamName:    req.TeamName,
+		Members:     members,
+		OpenReviews: pr.OpenReviews(req.OpenReviews),
+	}
+}
+
+func (req PostTeamAddMembersJSONBody) UserIds() []string {
+	ids := make([]string, 0, len(req.Members))
+	for _, m := range req.Members {
+		ids = append(ids, m.UserId)
+	}
+	return ids
+}
+
+func TeamSetMemberActiveToModel(req PostTeamSetMemberActiveJSONBody) pr.TeamSetMemberActive {
+	return pr.TeamSetMemberActive{
+		TeamName: req.TeamName,
+		UserId:   req.UserId,
+		IsActive: *req.IsActive,
+	}
+}
+
+// MembershipRoleToModel — роль из запроса; nil — роль не указана.
+func MembershipRoleToModel(role *openapi.MembershipRole) pr.MembershipRole {
+	if role == nil {
+		return ""
+	}
+	return pr.MembershipRole(*role)
+}
+
+// ValidMembershipRoles проверяет роли участников, не покрытые тегами validate.
+func ValidMembershipRoles(members []openapi.TeamMember) bool {
+	for _, m := range members {
+		if m.Role == nil {
+			continue
+		}
+		switch *m.Role {
+		case openapi.MembershipRoleMember, openapi.MembershipRoleLead:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ValidLeadRules проверяет значения правил, не покрытые тегами validate:
+// value обязателен везде, кроме fallback, а для author_seniority — это грейд.
+func ValidLeadRules(rules []LeadRuleBody) bool {
+	for _, r := range rules {
+		switch pr.LeadRuleKind(r.Kind) {
+		case pr.LeadRuleFallback:
+			if r.Value != "" {
+				return false
+			}
+		case pr.LeadRuleAuthorSeniority:
+			if r.Value == "" || !ValidSeniority(&r.Value) {
+				return false
+			}
+		default:
+			if r.Value == "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TeamSetLeadRulesToModel(req PostTeamSetLeadRulesJSONBody) pr.TeamSetLeadRules {
+	rules := make([]pr.LeadRule, 0, len(req.Rules))
+	for _, r := range req.Rules {
+		rules = append(rules, pr.LeadRule{Kind: pr.LeadRuleKind(r.Kind), Value: r.Value})
+	}
+	return pr.TeamSetLeadRules{
+		TeamName: req.TeamName,
+		UserId:   req.UserId,
+		Rules:    rules,
+	}
+}
+
+func CodeownersUploadToModel(req PostTeamCodeownersJSONBody) pr.CodeownersUpload {
+	return pr.CodeownersUpload{
+		TeamName: req.TeamName,
+		Content:  req.Content,
+	}
+}
+
+func CodeownersReportFromModel(r pr.CodeownersReport) openapi.CodeownersReport {
+	rules := make([]openapi.CodeownersRule, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		rules = append(rules, openapi.CodeownersRule{
+			Line:    rule.Line,
+			Pattern: rule.Pattern,
+			Users:   append([]string{}, rule.Users...),
+			Teams:   append([]string{}, rule.Teams...),
+		})
+	}
+	errs := make([]openapi.CodeownersError, 0, len(r.Errors))
+	for _, e := range r.Errors {
+		errs = append(errs, openapi.CodeownersError{Line: e.Line, Message: e.Message})
+	}
+	return openapi.CodeownersReport{
+		TeamName: r.TeamName,
+		Valid:    len(r.Errors) == 0,
+		Rules:    rules,
+		Errors:   errs,
+	}
+}
+
+// ValidSelectionMode проверяет необязательный selection_mode из /team/add.
+func ValidSelectionMode(mode *openapi.SelectionMode) bool {
+	return mode == nil || *mode == openapi.Random || *mode == openapi.Spread
+}
+
+func TeamSetMaxOpenReviewsToModel(req PostTeamSetMaxOpenReviewsJSONBody) pr.TeamSetMaxOpenReviews {
+	return pr.TeamSetMaxOpenReviews{
+		TeamName:       req.TeamName,
+		MaxOpenReviews: *req.MaxOpenReviews,
+	}
+}
+
+func TeamSetSelectionModeToModel(req PostTeamSetSelectionModeJSONBody) pr.TeamSetSelectionMode {
+	return pr.TeamSetSelectionMode{
+		TeamName:           req.TeamName,
+		Mode:               pr.SelectionMode(req.SelectionMode),
+		PreferWorkingHours: req.PreferWorkingHours,
+	}
+}
+
+func TeamRemoveMembersToModel(req PostTeamRemoveMembersJSONBody) pr.TeamRemoveMembers {
+	return pr.TeamRemoveMembers{
+		TeamName:    req.TeamName,
+		UserIds:     req.UserIds,
+		OpenReviews: pr.OpenReviews(req.OpenReviews),
+	}
+}
+
+func TeamRenameToModel(req PostTeamRenameJSONBody) pr.TeamRename {
+	return pr.TeamRename{
+		TeamName:    req.TeamName,
+		NewTeamName: req.NewTeamName,
+	}
+}
+
+func TeamSetParentToModel(req PostTeamSetParentJSONBody) pr.TeamSetParent {
+	r := pr.TeamSetParent{TeamName: req.TeamName}
+	if req.ParentTeam != nil {
+		r.ParentTeam = *req.ParentTeam
+	}
+	return r
+}
+
+func TeamTreeFromModel(nodes []pr.TeamNode) TeamTreeResponse {
+	return TeamTreeResponse{Teams: teamTreeNodes(nodes)}
+}
+
+func teamTreeNodes(nodes []pr.TeamNode) []openapi.TeamTreeNode {
+	resp := make([]openapi.TeamTreeNode, 0, len(nodes))
+	for _, n := range nodes {
+		resp = append(resp, openapi.TeamTreeNode{
+			TeamName:     n.TeamName,
+			MembersCount: n.Members,
+			Children:     teamTreeNodes(n.Children),
+		})
+	}
+	return resp
+}
+
+func TeamStatsFromModel(stats pr.TeamStats) TeamStatsResponse {
+	teams := make([]openapi.TeamStatsRow, 0, len(stats.Teams))
+	for _, t := range stats.Teams {
+		teams = append(teams, teamStatsRow(t))
+	}
+	return TeamStatsResponse{Total: teamStatsRow(stats.Total), Teams: teams}
+}
+
+func teamStatsRow(r pr.TeamStatsRow) openapi.TeamStatsRow {
+	return openapi.TeamStatsRow{
+		TeamName:           r.TeamName,
+		Members:            r.Members,
+		ActiveMembers:      r.ActiveMembers,
+		OpenPullRequests:   r.OpenPullRequests,
+		MergedPullRequests: r.MergedPullRequests,
+		OpenReviews:        r.OpenReviews,
+	}
+}
+
+func TeamDeleteToModel(p openapi.DeleteTeamParams) pr.TeamDelete {
+	return pr.TeamDelete{
+		TeamName:    p.TeamName,
+		OpenReviews: pr.OpenReviews(p.OpenReviews),
+	}
+}
+
+func TeamFromModel(t pr.Team) openapi.Team {
+	members := make([]openapi.TeamMember, 0, len(t.Members))
+	for _, m := range t.Members {
+		members = append(members, openapi.TeamMember{
+			IsActive:  m.IsActive,
+			UserId:    m.UserId,
+			Username:  m.Username,
+			Role:      membershipRoleFromModel(m.Role),
+			LeadRules: leadRulesFromModel(m.Role, m.LeadRules),
+		})
+	}
+	resp := openapi.Team{
+		Members:  members,
+		TeamName: t.TeamName,
+	}
+	if t.ParentTeam != "" {
+		parent := t.ParentTeam
+		resp.ParentTeam = &parent
+	}
+	if t.SelectionMode != "" {
+		mode := openapi.SelectionMode(t.SelectionMode)
+		resp.SelectionMode = &mode
+	}
+	if t.MaxOpenReviews > 0 {
+		limit := t.MaxOpenReviews
+		resp.MaxOpenReviews = &limit
+	}
+	prefer := t.PreferWorkingHours
+	resp.PreferWorkingHours = &prefer
+	return resp
+}
+
+func membershipRoleFromModel(role pr.MembershipRole) *openapi.MembershipRole {
+	if role == "" {
+		return nil
+	}
+	r := openapi.MembershipRole(role)
+	return &r
+}
+
+// leadRulesFromModel — правила только у лидов; у лида без правил — пустой список.
+func leadRulesFromModel(role pr.MembershipRole, rules []pr.LeadRule) *[]openapi.LeadRule {
+	if role != pr.MembershipRoleLead {
+		return nil
+	}
+	resp := make([]openapi.LeadRule, 0, len(rules))
+	for _, r := range rules {
+		rule := openapi.LeadRule{Kind: openapi.LeadRuleKind(r.Kind)}
+		if r.Value != "" {
+			value := r.Value
+			rule.Value = &value
+		}
+		resp = append(resp, rule)
+	}
+	return &resp
+}
+
+func ReassignmentsFromModel(rs []pr.Reassignment) []openapi.Reassignment {
+	resp := make([]openapi.Reassignment, 0, len(rs))
+	for _, r := range rs {
+		item := openapi.Reassignment{
+			PullRequestId: r.PullRequestId,
+			OldUserId:     r.OldUserId,
+		}
+		if r.NewUserId != "" {
+			newUserID := r.NewUserId
+			item.NewUserId = &newUserID
+		}
+		resp = append(resp, item)
+	}
+	return resp
+}
+
+func TeamChangeResultFromModel(res pr.TeamChangeResult) openapi.TeamChangeResult {
+	return openapi.TeamChangeResult{
+		Team:          TeamFromModel(res.Team),
+		Reassignments: ReassignmentsFromModel(res.Reassignments),
+	}
+}
@@ -0,0 +1,281 @@
+package api_dto
+
+import (
+	"fmt"
+	"pr-service/internal/domain/pr"
+	"pr-service/internal/infrastructure/http/openapi"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultUsersLimit — размер страницы /users/list по умолчанию
+	DefaultUsersLimit = 100
+	MaxUsersLimit     = 1000
+)
+
+type PostUsersUpdateJSONBody struct {
+	UserId         string  `json:"user_id" validate:"required"`
+	Username       *string `json:"username" validate:"omitempty,min=1"`
+	TeamName       *string `json:"team_name"`
+	Seniority      *string `json:"seniority"`
+	MaxOpenReviews *int    `json:"max_open_reviews" validate:"omitempty,min=0"`
+	OpenReviews    string  `json:"open_reviews" validate:"omitempty,oneof=keep reassign"`
+}
+
+type SkillBody struct {
+	Tag   string `json:"tag" validate:"required"`
+	Level string `json:"level" validate:"required,oneof=basic intermediate expert"`
+}
+
+type PostUsersSetSkillsJSONBody struct {
+	UserId string      `json:"user_id" validate:"required"`
+	Skills []SkillBody `json:"skills" validate:"required,dive"`
+}
+
+type WorkingHoursBody struct {
+	Timezone string `json:"timezone" validate:"required"`
+	Start    string `json:"start" validate:"required"`
+	End      string `json:"end" validate:"required"`
+	Days     []int  `json:"days" validate:"required,min=1,dive,min=1,max=7"`
+}
+
+type PostUsersSetWorkingHoursJSONBody struct {
+	UserId string `json:"user_id" validate:"required"`
+	// WorkingHours == nil снимает рабочее время
+	WorkingHours *WorkingHoursBody `json:"working_hours"`
+}
+
+type PostUsersAnonymizeJSONBody struct {
+	UserId      string `json:"user_id" validate:"required"`
+	OpenReviews string `json:"open_reviews" validate:"required,oneof=keep reassign"`
+}
+
+type GetUsersListParams struct {
+	Limit int `validate:"min=1,max=1000"`
+}
+
+func UserUpdateToModel(req PostUsersUpdateJSONBody) pr.UserUpdate {
+	openReviews := pr.OpenReviewsKeep
+	if req.OpenReviews != "" {
+		openReviews = pr.OpenReviews(req.OpenReviews)
+	}
+	r := pr.UserUpdate{
+		UserId:         req.UserId,
+		Username:       req.Username,
+		TeamName:       req.TeamName,
+		MaxOpenReviews: req.MaxOpenReviews,
+		OpenReviews:    openReviews,
+	}
+	if req.Seniority != nil {
+		seniority := pr.Seniority(*req.Seniority)
+		r.Seniority = &seniority
+	}
+	return r
+}
+
+// ValidSeniority проверяет грейд из /users/update; пустая строка снимает грейд.
+func ValidSeniority(s *string) bool {
+	if s == nil {
+		return true
+	}
+	switch pr.Seniority(*s) {
+	case "", pr.SeniorityJunior, pr.SeniorityMiddle, pr.SenioritySenior:
+		return true
+	}
+	return false
+}
+
+// ValidSkills проверяет, что после нормализации теги непустые и не повторяются.
+func ValidSkills(skills []SkillBody) bool {
+	seen := make(map[string]bool, len(skills))
+	for _, sk := range skills {
+		tag := pr.NormalizeSkillTag(sk.Tag)
+		if tag == "" || seen[tag] {
+			return false
+		}
+		seen[tag] = true
+	}
+	return true
+}
+
+func UserSetSkillsToModel(req PostUsersSetSkillsJSONBody) pr.UserSetSkills {
+	skills := make([]pr.Skill, 0, len(req.Skills))
+	for _, sk := range req.Skills {
+		skills = append(skills, pr.Skill{
+			Tag:   pr.NormalizeSkillTag(sk.Tag),
+			Level: pr.SkillLevel(sk.Level),
+		})
+	}
+	return pr.UserSetSkills{UserId: req.UserId, Skills: skills}
+}
+
+// ValidWorkingHours проверяет часовой пояс и время «ЧЧ:ММ»; конец дня
+// может быть 24:00 и должен быть позже начала.
+func ValidWorkingHours(w *WorkingHoursBody) bool {
+	if w == nil {
+		return true
+	}
+	if _, err := time.LoadLocation(w.Timezone); err != nil || w.Timezone == "Local" {
+		return false
+	}
+	start, ok := clockMinutes(w.Start)
+	if !ok || start == 24*60 {
+		return false
+	}
+	end, ok := clockMinutes(w.End)
+	return ok && start < end
+}
+
+func UserSetWorkingHoursToModel(req PostUsersSetWorkingHoursJSONBody) pr.UserSetWorkingHours {
+	r := pr.UserSetWorkingHours{UserId: req.UserId}
+	if req.WorkingHours == nil {
+		return r
+	}
+	start, _ := clockMinutes(req.WorkingHours.Start)
+	end, _ := clockMinutes(req.WorkingHours.End)
+	wh := &pr.WorkingHours{Timezone: req.WorkingHours.Timezone, Start: start, End: end}
+	for _, d := range req.WorkingHours.Days {
+		// дни в API — по ISO, 7 — воскресенье
+		day := time.Weekday(d % 7)
+		if !slices.Contains(wh.Days, day) {
+			wh.Days = append(wh.Days, day)
+		}
+	}
+	r.WorkingHours = wh
+	return r
+}
+
+// clockMinutes разбирает «ЧЧ:ММ» в минуты от полуночи; 24:00 — конец суток.
+func clockMinutes(s string) (int, bool) {
+	var h, m int
+	if n, err := fmt.Sscanf(s, "%2d:%2d", &h, &m); err != nil || n != 2 || len(s) != 5 {
+		return 0, false
+	}
+	if h == 24 && m == 0 {
+		return 24 * 60, true
+	}
+	if h < 0 || h > 23 || m < 0 || m > 59 {
+		return 0, false
+	}
+	return h*60 + m, true
+}
+
+func workingHoursFromModel(w *pr.WorkingHours) *openapi.WorkingHours {
+	if w == nil {
+		return nil
+	}
+	resp := &openapi.WorkingHours{
+		Timezone: w.Timezone,
+		Start:    fmt.Sprintf("%02d:%02d", w.Start/60, w.Start%60),
+		End:      fmt.Sprintf("%02d:%02d", w.End/60, w.End%60),
+		Days:     make([]int, 0, len(w.Days)),
+	}
+	for _, d := range w.Days {
+		if d == time.Sunday {
+			resp.Days = append(resp.Days, 7)
+			continue
+		}
+		resp.Days = append(resp.Days, int(d))
+	}
+	slices.Sort(resp.Days)
+	return resp
+}
+
+func UserAnonymizeToModel(req PostUsersAnonymizeJSONBody) pr.UserAnonymize {
+	return pr.UserAnonymize{
+		UserId:      req.UserId,
+		OpenReviews: pr.OpenReviews(req.OpenReviews),
+	}
+}
+
+func UsersListToModel(p openapi.GetUsersListParams) pr.UsersListFilter {
+	f := pr.UsersListFilter{
+		TeamName: p.TeamName,
+		IsActive: p.IsActive,
+		Limit:    DefaultUsersLimit,
+	}
+	if p.Limit != nil {
+		f.Limit = *p.Limit
+	}
+	if p.After != nil {
+		f.After = *p.After
+	}
+	return f
+}
+
+func UserFromModel(u pr.User) openapi.User {
+	resp := openapi.User{
+		UserId:       u.UserId,
+		Username:     u.Username,
+		IsActive:     u.IsActive,
+		AnonymizedAt: u.AnonymizedAt,
+	}
+	if u.Teams != nil {
+		teams := u.Teams
+		resp.Teams = &teams
+	}
+	if u.TeamName != "" {
+		teamName := u.TeamName
+		resp.TeamName = &teamName
+	}
+	if u.Seniority != "" {
+		seniority := openapi.Seniority(u.Seniority)
+		resp.Seniority = &seniority
+	}
+	if u.MaxOpenReviews > 0 {
+		limit := u.MaxOpenReviews
+		resp.MaxOpenReviews = &limit
+	}
+	resp.WorkingHours = workingHoursFromModel(u.WorkingHours)
+	if u.Skills != nil {
+		skills := make([]openapi.Skill, 0, len(u.Skills))
+		for _, sk := range u.Skills {
+			skills = append(skills, openapi.Skill{Tag: sk.Tag, Level: openapi.SkillLevel(sk.Level)})
+		}
+		resp.Skills = &skills
+	}
+	return resp
+}
+
+type UsersPageResponse struct {
+	Users     []openapi.User `json:"users"`
+	NextAfter *string        `json:"next_after"`
+}
+
+func UsersPageFromModel(page pr.UsersPage) UsersPageResponse {
+	users := make([]openapi.User, 0, len(page.Users))
+	for _, u := range page.Users {
+		users = append(users, UserFromModel(u))
+	}
+	resp := UsersPageResponse{Users: users}
+	if page.NextAfter != "" {
+		next := page.NextAfter
+		resp.NextAfter = &next
+	}
+	return resp
+}
+
+type UserPairingsResponse struct {
+	UserId   string            `json:"user_id"`
+	Pairings []openapi.Pairing `json:"pairings"`
+}
+
+func UserPairingsFromModel(userID string, pairings []pr.Pairing) UserPairingsResponse {
+	resp := UserPairingsResponse{UserId: userID, Pairings: make([]openapi.Pairing, 0, len(pairings))}
+	for _, p := range pairings {
+		resp.Pairings = append(resp.Pairings, openapi.Pairing{
+			ReviewerId: p.ReviewerId,
+			Count:      p.Count,
+			LastAt:     p.LastAt,
+		})
+	}
+	return resp
+}
+
+func UserChangeResultFromModel(res pr.UserChangeResult) openapi.UserChangeResult {
+	return openapi.UserChangeResult{
+		User:          UserFromModel(res.User),
+		Reassignments: ReassignmentsFromModel(res.Reassignments),
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"pr-service/internal/infrastructure/http/openapi"
+	"strconv"
+	"strings"
+)
+
+var errInvalidIfMatch = errors.New("invalid If-Match header")
+
+// parseIfMatch возвращает версию PR из If-Match. 0 — заголовок не задан или
+// равен "*", версия не проверяется. Слабые ETag (W/"3") принимаются как сильные.
+func parseIfMatch(header *openapi.IfMatch) (int64, error) {
+	if header == nil {
+		return 0, nil
+	}
+	v := strings.TrimSpace(*header)
+	if v == "" || v == "*" {
+		return 0, nil
+	}
+	v = strings.TrimPrefix(v, "W/")
+	if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(v[1:len(v)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"pr-service/internal/infrastructure/http/openapi"
+	"testing"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	header := func(v string) *openapi.IfMatch { return &v }
+
+	tests := []struct {
+		name    string
+		header  *openapi.IfMatch
+		want    int64
+		wantErr bool
+	}{
+		{"заголовок не задан", nil, 0, false},
+		{"пустой заголовок", header(""), 0, false},
+		{"звёздочка", header("*"), 0, false},
+		{"звёздочка с пробелами", header("  * "), 0, false},
+		{"версия в кавычках", header(`"3"`), 3, false},
+		{"пробелы вокруг", header(` "42" `), 42, false},
+		{"слабый ETag", header(`W/"7"`), 7, false},
+		{"без кавычек", header("3"), 0, true},
+		{"одна кавычка", header(`"3`), 0, true},
+		{"только кавычка", header(`"`), 0, true},
+		{"пустые кавычки", header(`""`), 0, true},
+		{"слабый без кавычек", header("W/3"), 0, true},
+		{"строчная w/", header(`w/"3"`), 0, true},
+		{"не число", header(`"abc"`), 0, true},
+		{"ноль", header(`"0"`), 0, true},
+		{"отрицательная версия", header(`"-1"`), 0, true},
+		{"список ETag", header(`"1", "2"`), 0, true},
+		{"переполнение", header(`"99999999999999999999"`), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseIfMatch(tt.header)
+			if tt.wantErr {
+				if !errors.Is(err, errInvalidIfMatch) {
+					t.Fatalf("parseIfMatch() error = %v, want %v", err, errInvalidIfMatch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseIfMatch() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseIfMatch() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetETagRoundTrip(t *testing.T) {
+	w := httptest.NewRecorder()
+	setETag(w, 12)
+	etag := w.Header().Get("ETag")
+	if etag != `"12"` {
+		t.Fatalf("ETag = %s, want %q", etag, `"12"`)
+	}
+	if got, err := parseIfMatch(&etag); err != nil || got != 12 {
+		t.Errorf("parseIfMatch(%s) = %d, %v, want 12", etag, got, err)
+	}
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pr-service/internal/domain/pr"
+	dto "pr-service/internal/infrastructure/http/handlers/dto"
+	"pr-service/internal/infrastructure/http/middleware"
+	openapi "pr-service/internal/infrastructure/http/openapi"
+	"pr-service/internal/infrastructure/http/transport"
+	"pr-service/internal/infrastructure/storage/postgres"
+	"pr-service/pkg/sl_logger/sl"
+	validateResp "pr-service/pkg/validator"
+	"time"
+
+	"github.com/go-playground/validator"
+)
+
+// exportFlushRows — через сколько строк выгрузка сбрасывается клиенту
+const exportFlushRows = 500
+
+type exportRow interface {
+	CSVRecord() []string
+}
+
+// exportWriter пишет строки выгрузки в ответ по мере чтения из базы.
+// Заголовки отправляются с первой строкой, поэтому ошибку до неё ещё можно
+// вернуть обычным JSON-ответом.
+type exportWriter struct {
+	w      http.ResponseWriter
+	rc     *http.ResponseController
+	format string
+	name   string
+	header []string
+	csv    *csv.Writer
+	json   *json.Encoder
+	// started — заголовки ответа уже отправлены
+	started bool
+	rows    int
+}
+
+func newExportWriter(w http.ResponseWriter, format, name string, header []string) *exportWriter {
+	return &exportWriter{
+		w:      w,
+		rc:     http.NewResponseController(w),
+		format: format,
+		name:   name,
+		header: header,
+	}
+}
+
+func (e *exportWriter) start() error {
+	e.started = true
+	// выгрузка дольше WriteTimeout сервера не должна обрываться
+	_ = e.rc.SetWriteDeadline(time.Time{})
+
+	ext, contentType := "csv", "text/csv; charset=utf-8"
+	if e.format == "ndjson" {
+		ext, contentType = "ndjson", "application/x-ndjson"
+	}
+	e.w.Header().Set("Content-Type", contentType)
+	e.w.Header().Set("Content-Disposition", `attachment; filename="`+e.name+"."+ext+`"`)
+	e.w.WriteHeader(http.StatusOK)
+
+	if e.format == "ndjson" {
+		e.json = json.NewEncoder(e.w)
+		return nil
+	}
+	e.csv = csv.NewWriter(e.w)
+	return e.csv.Write(e.header)
+}
+
+func (e *exportWriter) write(row exportRow) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	var err error
+	if e.json != nil {
+		err = e.json.Encode(row)
+	} else {
+		err = e.csv.Write(row.CSVRecord())
+	}
+	if err != nil {
+		return err
+	}
+	e.rows++
+	if e.rows%exportFlushRows == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+func (e *exportWriter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return e.rc.Flush()
+}
+
+// finish завершает выгрузку. Пустая выгрузка — только заголовок csv.
+// Ошибка после отправки заголовков обрывает соединение: иначе клиент получил бы
+// неполный файл как целый.
+func (e *exportWriter) finish(log *slog.Logger, err error, onErr func(error)) {
+	switch {
+	case err != nil && !e.started:
+		onErr(err)
+	case err != nil:
+		log.Error("export interrupted", slog.Int("rows", e.rows), sl.Err(err))
+		_ = e.flush()
+		panic(http.ErrAbortHandler)
+	default:
+		if !e.started {
+			if err := e.start(); err != nil {
+				log.Error("failed to write export", sl.Err(err))
+				return
+			}
+		}
+		if err := e.flush(); err != nil {
+			log.Error("failed to write export", sl.Err(err))
+			return
+		}
+		log.Info("export finished", slog.Int("rows", e.rows))
+	}
+}
+
+func exportInternalErr(w http.ResponseWriter, log *slog.Logger) func(error) {
+	return func(err error) {
+		log.Error("export failed", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+	}
+}
+
+func validExport(w http.ResponseWriter, log *slog.Logger, params dto.ExportParams) bool {
+	if err := validator.New().Struct(params); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return false
+	}
+	return true
+}
+
+// Выгрузка PR потоком
+// (GET /export/pullRequests)
+func (h *API) GetExportPullRequests(w http.ResponseWriter, r *http.Request, params openapi.GetExportPullRequestsParams) {
+	const op = "handlers.GetExportPullRequests"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	format := dto.ExportFormat(params.Format)
+	check := dto.ExportParams{Format: format}
+	if params.Status != nil {
+		check.Status = *params.Status
+	}
+	if !validExport(w, log, check) {
+		return
+	}
+
+	filter := pr.PullRequestsFilter{
+		Status:      params.Status,
+		TeamName:    params.TeamName,
+		AuthorId:    params.AuthorId,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+	}
+	out := newExportWriter(w, format, "pull_requests", dto.PullRequestExportHeader)
+	err := h.Svc.ExportPullRequests(r.Context(), filter, func(p pr.PullRequest) error {
+		return out.write(dto.PullRequestExportFromModel(p))
+	})
+	out.finish(log, err, exportInternalErr(w, log))
+}
+
+// Выгрузка пользователей потоком
+// (GET /export/users)
+func (h *API) GetExportUsers(w http.ResponseWriter, r *http.Request, params openapi.GetExportUsersParams) {
+	const op = "handlers.GetExportUsers"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	format := dto.ExportFormat(params.Format)
+	if !validExport(w, log, dto.ExportParams{Format: format}) {
+		return
+	}
+
+	filter := pr.UsersListFilter{
+		TeamName: params.TeamName,
+		IsActive: params.IsActive,
+	}
+	if params.After != nil {
+		filter.After = *params.After
+	}
+	out := newExportWriter(w, format, "users", dto.UserExportHeader)
+	err := h.Svc.ExportUsers(r.Context(), filter, func(u pr.User) error {
+		return out.write(dto.UserExportFromModel(u))
+	})
+	out.finish(log, err, exportInternalErr(w, log))
+}
+
+// Выгрузка команд потоком
+// (GET /export/teams)
+func (h *API) GetExportTeams(w http.ResponseWriter, r *http.Request, params openapi.GetExportTeamsParams) {
+	const op = "handlers.GetExportTeams"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	format := dto.ExportFormat(params.Format)
+	if !validExport(w, log, dto.ExportParams{Format: format}) {
+		return
+	}
+
+	root := ""
+	if params.TeamName != nil {
+		root = *params.TeamName
+	}
+	out := newExportWriter(w, format, "teams", dto.TeamExportHeader)
+	err := h.Svc.ExportTeams(r.Context(), root, func(t pr.TeamExport) error {
+		return out.write(dto.TeamExportFromModel(t))
+	})
+	out.finish(log, err, func(err error) {
+		if errors.Is(err, postgres.ErrNotFound) {
+			log.Warn("team not found", sl.Err(err))
+			responseErr(w, http.StatusNotFound, "команда не найдена")
+			return
+		}
+		exportInternalErr(w, log)(err)
+	})
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"pr-service/internal/health"
+	openapi "pr-service/internal/infrastructure/http/openapi"
+	"pr-service/internal/infrastructure/http/transport"
+)
+
+// Liveness-проба (процесс жив и обслуживает HTTP)
+// (GET /health/live)
+func (h *API) GetHealthLive(w http.ResponseWriter, r *http.Request) {
+	transport.WriteJSON(w, http.StatusOK, openapi.HealthReport{Status: openapi.HealthReportStatusUp})
+}
+
+// Readiness-проба (БД, миграции, фоновые воркеры)
+// (GET /health/ready)
+func (h *API) GetHealthReady(w http.ResponseWriter, r *http.Request) {
+	if h.Health == nil {
+		transport.WriteJSON(w, http.StatusOK, openapi.HealthReport{Status: openapi.HealthReportStatusUp})
+		return
+	}
+
+	report := h.Health.Run(r.Context())
+
+	checks := make(map[string]openapi.HealthCheck, len(report.Checks))
+	for name, res := range report.Checks {
+		check := openapi.HealthCheck{Status: openapi.HealthCheckStatus(res.Status)}
+		if res.Error != "" {
+			check.Error = &res.Error
+		}
+		if res.Details != nil {
+			details := res.Details
+			check.Details = &details
+		}
+		checks[name] = check
+	}
+
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	transport.WriteJSON(w, status, openapi.HealthReport{
+		Status: openapi.HealthReportStatus(report.Status),
+		Checks: &checks,
+	})
+}
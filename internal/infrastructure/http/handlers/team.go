@@ -0,0 +1,511 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	dto "pr-service/internal/infrastructure/http/handlers/dto"
+	"pr-service/internal/infrastructure/http/middleware"
+	openapi "pr-service/internal/infrastructure/http/openapi"
+	"pr-service/internal/infrastructure/http/transport"
+	"pr-service/internal/infrastructure/storage/postgres"
+	"pr-service/pkg/sl_logger/sl"
+	validateResp "pr-service/pkg/validator"
+
+	"github.com/go-playground/validator"
+)
+
+// Добавить участников в существующую команду (создаёт/обновляет/переводит пользователей)
+// (POST /team/addMembers)
+func (h *API) PostTeamAddMembers(w http.ResponseWriter, r *http.Request, _ openapi.PostTeamAddMembersParams) {
+	const op = "handlers.PostTeamAddMembers"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostTeamAddMembersJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanAddMembers(r.Context(), p, req.TeamName, req.UserIds())
+	}) {
+		return
+	}
+
+	res, err := h.Svc.TeamAddMembers(r.Context(), dto.TeamAddMembersToModel(req))
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("team members added",
+		slog.String("team", req.TeamName),
+		slog.Int("reassignments", len(res.Reassignments)),
+	)
+	transport.WriteJSON(w, http.StatusOK, dto.TeamChangeResultFromModel(res))
+}
+
+// Включить или выключить участие пользователя в ревью команды
+// (POST /team/setMemberActive)
+func (h *API) PostTeamSetMemberActive(w http.ResponseWriter, r *http.Request, _ openapi.PostTeamSetMemberActiveParams) {
+	const op = "handlers.PostTeamSetMemberActive"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostTeamSetMemberActiveJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanManageTeam(r.Context(), p, req.TeamName)
+	}) {
+		return
+	}
+
+	team, err := h.Svc.TeamSetMemberActive(r.Context(), dto.TeamSetMemberActiveToModel(req))
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("team membership activity changed",
+		slog.String("team", req.TeamName),
+		slog.String("user_id", req.UserId),
+		slog.Bool("is_active", *req.IsActive),
+	)
+	transport.WriteJSON(w, http.StatusOK, dto.TeamFromModel(team))
+}
+
+// Задать правила, по которым лид добавляется ревьювером
+// (POST /team/setLeadRules)
+func (h *API) PostTeamSetLeadRules(w http.ResponseWriter, r *http.Request, _ openapi.PostTeamSetLeadRulesParams) {
+	const op = "handlers.PostTeamSetLeadRules"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostTeamSetLeadRulesJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+	if !dto.ValidLeadRules(req.Rules) {
+		log.Warn("invalid lead rules")
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanManageTeam(r.Context(), p, req.TeamName)
+	}) {
+		return
+	}
+
+	team, err := h.Svc.TeamSetLeadRules(r.Context(), dto.TeamSetLeadRulesToModel(req))
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("lead rules set",
+		slog.String("team", req.TeamName),
+		slog.String("user_id", req.UserId),
+		slog.Int("rules", len(req.Rules)),
+	)
+	transport.WriteJSON(w, http.StatusOK, dto.TeamFromModel(team))
+}
+
+// Изменить лимит открытых ревью участников команды по умолчанию
+// (POST /team/setMaxOpenReviews)
+func (h *API) PostTeamSetMaxOpenReviews(w http.ResponseWriter, r *http.Request, _ openapi.PostTeamSetMaxOpenReviewsParams) {
+	const op = "handlers.PostTeamSetMaxOpenReviews"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostTeamSetMaxOpenReviewsJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanManageTeam(r.Context(), p, req.TeamName)
+	}) {
+		return
+	}
+
+	team, err := h.Svc.TeamSetMaxOpenReviews(r.Context(), dto.TeamSetMaxOpenReviewsToModel(req))
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("max open reviews set",
+		slog.String("team", req.TeamName),
+		slog.Int("max_open_reviews", *req.MaxOpenReviews),
+	)
+	transport.WriteJSON(w, http.StatusOK, dto.TeamFromModel(team))
+}
+
+// Изменить режим подбора ревьюверов команды
+// (POST /team/setSelectionMode)
+func (h *API) PostTeamSetSelectionMode(w http.ResponseWriter, r *http.Request, _ openapi.PostTeamSetSelectionModeParams) {
+	const op = "handlers.PostTeamSetSelectionMode"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostTeamSetSelectionModeJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanManageTeam(r.Context(), p, req.TeamName)
+	}) {
+		return
+	}
+
+	team, err := h.Svc.TeamSetSelectionMode(r.Context(), dto.TeamSetSelectionModeToModel(req))
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("selection mode set",
+		slog.String("team", req.TeamName),
+		slog.String("mode", req.SelectionMode),
+		slog.Any("prefer_working_hours", req.PreferWorkingHours),
+	)
+	transport.WriteJSON(w, http.StatusOK, dto.TeamFromModel(team))
+}
+
+// Проверить CODEOWNERS команды
+// (POST /team/validateCodeowners)
+func (h *API) PostTeamValidateCodeowners(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.PostTeamValidateCodeowners"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostTeamCodeownersJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	report, err := h.Svc.CodeownersValidate(r.Context(), dto.CodeownersUploadToModel(req))
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusOK, dto.CodeownersReportFromModel(report))
+}
+
+// Загрузить CODEOWNERS команды
+// (POST /team/uploadCodeowners)
+func (h *API) PostTeamUploadCodeowners(w http.ResponseWriter, r *http.Request, _ openapi.PostTeamUploadCodeownersParams) {
+	const op = "handlers.PostTeamUploadCodeowners"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostTeamCodeownersJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanManageTeam(r.Context(), p, req.TeamName)
+	}) {
+		return
+	}
+
+	report, err := h.Svc.CodeownersUpload(r.Context(), dto.CodeownersUploadToModel(req))
+	if errors.Is(err, pr.ErrInvalidCodeowners) {
+		log.Warn("invalid codeowners", slog.Int("errors", len(report.Errors)))
+		transport.WriteJSON(w, http.StatusBadRequest, dto.CodeownersReportFromModel(report))
+		return
+	}
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("codeowners uploaded", slog.String("team", req.TeamName), slog.Int("rules", len(report.Rules)))
+	transport.WriteJSON(w, http.StatusOK, dto.CodeownersReportFromModel(report))
+}
+
+// Исключить участников из команды
+// (POST /team/removeMembers)
+func (h *API) PostTeamRemoveMembers(w http.ResponseWriter, r *http.Request, _ openapi.PostTeamRemoveMembersParams) {
+	const op = "handlers.PostTeamRemoveMembers"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostTeamRemoveMembersJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanManageTeam(r.Context(), p, req.TeamName)
+	}) {
+		return
+	}
+
+	res, err := h.Svc.TeamRemoveMembers(r.Context(), dto.TeamRemoveMembersToModel(req))
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("team members removed",
+		slog.String("team", req.TeamName),
+		slog.Int("reassignments", len(res.Reassignments)),
+	)
+	transport.WriteJSON(w, http.StatusOK, dto.TeamChangeResultFromModel(res))
+}
+
+// Переименовать команду
+// (POST /team/rename)
+func (h *API) PostTeamRename(w http.ResponseWriter, r *http.Request, _ openapi.PostTeamRenameParams) {
+	const op = "handlers.PostTeamRename"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostTeamRenameJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanManageTeam(r.Context(), p, req.TeamName)
+	}) {
+		return
+	}
+
+	team, err := h.Svc.TeamRename(r.Context(), dto.TeamRenameToModel(req))
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("team renamed", slog.String("team", req.TeamName), slog.String("new_team", req.NewTeamName))
+	transport.WriteJSON(w, http.StatusOK, dto.TeamFromModel(team))
+}
+
+// Иерархия команд
+// (GET /team/tree)
+func (h *API) GetTeamTree(w http.ResponseWriter, r *http.Request, params openapi.GetTeamTreeParams) {
+	const op = "handlers.GetTeamTree"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	root := ""
+	if params.TeamName != nil {
+		root = *params.TeamName
+	}
+
+	tree, err := h.Svc.TeamTree(r.Context(), root)
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusOK, dto.TeamTreeFromModel(tree))
+}
+
+// Показатели команды вместе с подкомандами
+// (GET /team/stats)
+func (h *API) GetTeamStats(w http.ResponseWriter, r *http.Request, params openapi.GetTeamStatsParams) {
+	const op = "handlers.GetTeamStats"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+		slog.String("team_name", params.TeamName),
+	)
+
+	stats, err := h.Svc.TeamStats(r.Context(), params.TeamName)
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusOK, dto.TeamStatsFromModel(stats))
+}
+
+// Перенести команду в иерархии (только admin)
+// (POST /team/setParent)
+func (h *API) PostTeamSetParent(w http.ResponseWriter, r *http.Request, _ openapi.PostTeamSetParentParams) {
+	const op = "handlers.PostTeamSetParent"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostTeamSetParentJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if !h.authorize(w, r, log, h.Policy.CanManageHierarchy) {
+		return
+	}
+
+	team, err := h.Svc.TeamSetParent(r.Context(), dto.TeamSetParentToModel(req))
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("team moved", slog.String("team", team.TeamName), slog.String("parent_team", team.ParentTeam))
+	transport.WriteJSON(w, http.StatusOK, dto.TeamFromModel(team))
+}
+
+// Удалить команду (только admin)
+// (DELETE /team)
+func (h *API) DeleteTeam(w http.ResponseWriter, r *http.Request, params openapi.DeleteTeamParams) {
+	const op = "handlers.DeleteTeam"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+		slog.String("team_name", params.TeamName),
+	)
+
+	if err := validator.New().Struct(dto.DeleteTeamParams{
+		TeamName:    params.TeamName,
+		OpenReviews: string(params.OpenReviews),
+	}); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if !h.authorize(w, r, log, h.Policy.CanDeleteTeam) {
+		return
+	}
+
+	reassignments, err := h.Svc.TeamDelete(r.Context(), dto.TeamDeleteToModel(params))
+	if err != nil {
+		h.teamChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("team deleted", slog.Int("reassignments", len(reassignments)))
+	transport.WriteJSON(w, http.StatusOK, struct {
+		Reassignments []openapi.Reassignment `json:"reassignments"`
+	}{Reassignments: dto.ReassignmentsFromModel(reassignments)})
+}
+
+func (h *API) teamChangeErr(w http.ResponseWriter, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, postgres.ErrNotFound):
+		log.Warn("team or member not found", sl.Err(err))
+		responseErr(w, http.StatusNotFound, "команда не найдена или пользователь не состоит в ней")
+	case errors.Is(err, postgres.ErrTeamExists):
+		responseErr(w, http.StatusConflict, "команда уже существует")
+	case errors.Is(err, postgres.ErrUserAnonymized):
+		responseErr(w, http.StatusConflict, postgres.ErrUserAnonymized.Error())
+	case errors.Is(err, postgres.ErrTeamCycle):
+		responseErr(w, http.StatusConflict, postgres.ErrTeamCycle.Error())
+	case errors.Is(err, postgres.ErrNotTeamLead):
+		responseErr(w, http.StatusBadRequest, postgres.ErrNotTeamLead.Error())
+	case errors.Is(err, postgres.ErrNoCandidate):
+		responseErr(w, http.StatusBadRequest, "нужен хотя бы один участник")
+	case errors.Is(err, postgres.ErrConcurrentUpdate):
+		responseCodedErr(w, http.StatusConflict, postgres.ErrConcurrentUpdate)
+	default:
+		log.Error("team change failed", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+	}
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	dto "pr-service/internal/infrastructure/http/handlers/dto"
+	"pr-service/internal/infrastructure/http/middleware"
+	openapi "pr-service/internal/infrastructure/http/openapi"
+	"pr-service/internal/infrastructure/http/transport"
+	"pr-service/internal/infrastructure/storage/postgres"
+	"pr-service/pkg/sl_logger/sl"
+	validateResp "pr-service/pkg/validator"
+
+	"github.com/go-playground/validator"
+)
+
+// Получить пользователя
+// (GET /users/get)
+func (h *API) GetUsersGet(w http.ResponseWriter, r *http.Request, params openapi.GetUsersGetParams) {
+	const op = "handlers.GetUsersGet"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+		slog.String("user_id", params.UserId),
+	)
+
+	user, err := h.Svc.UserGet(r.Context(), params.UserId)
+	if err != nil {
+		h.userChangeErr(w, log, err)
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusOK, dto.UserFromModel(user))
+}
+
+// История пар автор–ревьювер
+// (GET /users/pairings)
+func (h *API) GetUsersPairings(w http.ResponseWriter, r *http.Request, params openapi.GetUsersPairingsParams) {
+	const op = "handlers.GetUsersPairings"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+		slog.String("user_id", params.UserId),
+	)
+
+	pairings, err := h.Svc.UserPairings(r.Context(), params.UserId)
+	if err != nil {
+		h.userChangeErr(w, log, err)
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusOK, dto.UserPairingsFromModel(params.UserId, pairings))
+}
+
+// Список пользователей с фильтрами
+// (GET /users/list)
+func (h *API) GetUsersList(w http.ResponseWriter, r *http.Request, params openapi.GetUsersListParams) {
+	const op = "handlers.GetUsersList"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	filter := dto.UsersListToModel(params)
+	if err := validator.New().Struct(dto.GetUsersListParams{Limit: filter.Limit}); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	page, err := h.Svc.UsersList(r.Context(), filter)
+	if err != nil {
+		log.Error("failed to list users", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+		return
+	}
+
+	transport.WriteJSON(w, http.StatusOK, dto.UsersPageFromModel(page))
+}
+
+// Изменить имя и/или команду пользователя
+// (POST /users/update)
+func (h *API) PostUsersUpdate(w http.ResponseWriter, r *http.Request, _ openapi.PostUsersUpdateParams) {
+	const op = "handlers.PostUsersUpdate"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostUsersUpdateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+	if !dto.ValidSeniority(req.Seniority) {
+		log.Warn("invalid seniority", slog.String("seniority", *req.Seniority))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanUpdateUser(r.Context(), p, req.UserId, req.TeamName, req.Seniority != nil || req.MaxOpenReviews != nil)
+	}) {
+		return
+	}
+
+	res, err := h.Svc.UserUpdate(r.Context(), dto.UserUpdateToModel(req))
+	if err != nil {
+		h.userChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("user updated",
+		slog.String("user_id", req.UserId),
+		slog.Int("reassignments", len(res.Reassignments)),
+	)
+	transport.WriteJSON(w, http.StatusOK, dto.UserChangeResultFromModel(res))
+}
+
+// Задать навыки пользователя
+// (POST /users/setSkills)
+func (h *API) PostUsersSetSkills(w http.ResponseWriter, r *http.Request, _ openapi.PostUsersSetSkillsParams) {
+	const op = "handlers.PostUsersSetSkills"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostUsersSetSkillsJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+	if !dto.ValidSkills(req.Skills) {
+		log.Warn("duplicate or empty skill tags")
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanUpdateUser(r.Context(), p, req.UserId, nil, false)
+	}) {
+		return
+	}
+
+	user, err := h.Svc.UserSetSkills(r.Context(), dto.UserSetSkillsToModel(req))
+	if err != nil {
+		h.userChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("user skills set", slog.String("user_id", req.UserId), slog.Int("skills", len(req.Skills)))
+	transport.WriteJSON(w, http.StatusOK, dto.UserFromModel(user))
+}
+
+// Задать рабочее время пользователя
+// (POST /users/setWorkingHours)
+func (h *API) PostUsersSetWorkingHours(w http.ResponseWriter, r *http.Request, _ openapi.PostUsersSetWorkingHoursParams) {
+	const op = "handlers.PostUsersSetWorkingHours"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostUsersSetWorkingHoursJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+	if !dto.ValidWorkingHours(req.WorkingHours) {
+		log.Warn("invalid working hours")
+		responseErr(w, http.StatusBadRequest, "неизвестный часовой пояс или некорректное время начала и конца дня")
+		return
+	}
+
+	if !h.authorize(w, r, log, func(p auth.Principal) error {
+		return h.Policy.CanUpdateUser(r.Context(), p, req.UserId, nil, false)
+	}) {
+		return
+	}
+
+	user, err := h.Svc.UserSetWorkingHours(r.Context(), dto.UserSetWorkingHoursToModel(req))
+	if err != nil {
+		h.userChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("user working hours set", slog.String("user_id", req.UserId), slog.Bool("cleared", req.WorkingHours == nil))
+	transport.WriteJSON(w, http.StatusOK, dto.UserFromModel(user))
+}
+
+// Анонимизировать пользователя (только admin)
+// (POST /users/anonymize)
+func (h *API) PostUsersAnonymize(w http.ResponseWriter, r *http.Request, _ openapi.PostUsersAnonymizeParams) {
+	const op = "handlers.PostUsersAnonymize"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetRequestID(r)),
+	)
+
+	var req dto.PostUsersAnonymizeJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, transport.ErrInvalidRequest.Error())
+		return
+	}
+	if err := validator.New().Struct(req); err != nil {
+		log.Warn("validation failed", sl.Err(err))
+		_ = transport.WriteJSON(w, http.StatusBadRequest, validateResp.ValidationError(err.(validator.ValidationErrors)))
+		return
+	}
+
+	if !h.authorize(w, r, log, h.Policy.CanAnonymizeUser) {
+		return
+	}
+
+	res, err := h.Svc.UserAnonymize(r.Context(), dto.UserAnonymizeToModel(req))
+	if err != nil {
+		h.userChangeErr(w, log, err)
+		return
+	}
+
+	log.Info("user anonymized",
+		slog.String("user_id", req.UserId),
+		slog.Int("reassignments", len(res.Reassignments)),
+	)
+	transport.WriteJSON(w, http.StatusOK, dto.UserChangeResultFromModel(res))
+}
+
+func (h *API) userChangeErr(w http.ResponseWriter, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, postgres.ErrNotFound):
+		log.Warn("user or team not found", sl.Err(err))
+		responseErr(w, http.StatusNotFound, "пользователь или команда не найдены")
+	case errors.Is(err, postgres.ErrUserAnonymized):
+		responseErr(w, http.StatusConflict, postgres.ErrUserAnonymized.Error())
+	case errors.Is(err, postgres.ErrConcurrentUpdate):
+		responseCodedErr(w, http.StatusConflict, postgres.ErrConcurrentUpdate)
+	case errors.Is(err, pr.ErrInvalidWorkingHours):
+		log.Warn("invalid working hours", sl.Err(err))
+		responseErr(w, http.StatusBadRequest, "в рабочем времени нет дней или начало дня не раньше конца")
+	default:
+		log.Error("user operation failed", sl.Err(err))
+		responseErr(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/infrastructure/http/openapi"
+	"pr-service/internal/infrastructure/http/transport"
+	"pr-service/pkg/sl_logger/sl"
+	"strings"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// Authenticator проверяет учётные данные запроса.
+type Authenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (auth.Principal, error)
+	AuthenticateBearer(ctx context.Context, token string) (auth.Principal, error)
+}
+
+// NewMWAuth требует API-ключ (X-API-Key) или JWT (Authorization: Bearer) и
+// кладёт principal в контекст запроса. Пути с префиксами из public
+// (health-пробы, метрики) пропускаются без проверки.
+func NewMWAuth(authn Authenticator, log *slog.Logger, public ...string) func(http.Handler) http.Handler {
+	log = log.With(slog.String("component", "middleware/auth"))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, prefix := range public {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			var (
+				principal auth.Principal
+				err       error
+			)
+			switch {
+			case r.Header.Get(APIKeyHeader) != "":
+				principal, err = authn.AuthenticateAPIKey(r.Context(), r.Header.Get(APIKeyHeader))
+			case strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "):
+				token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+				principal, err = authn.AuthenticateBearer(r.Context(), token)
+			default:
+				err = auth.ErrUnauthenticated
+			}
+
+			if err != nil {
+				entry := log.With(slog.String("request_id", GetRequestID(r)), sl.Err(err))
+				if errors.Is(err, auth.ErrUnauthenticated) ||
+					errors.Is(err, auth.ErrInvalidKey) ||
+					errors.Is(err, auth.ErrInvalidToken) {
+					entry.Warn("unauthenticated request")
+					w.Header().Set("WWW-Authenticate", `Bearer realm="pr-service"`)
+					WriteError(w, http.StatusUnauthorized, openapi.UNAUTHORIZED, "требуется аутентификация")
+					return
+				}
+				entry.Error("authentication failed")
+				WriteError(w, http.StatusInternalServerError, openapi.ErrorResponseErrorCode(http.StatusText(http.StatusInternalServerError)), "внутренняя ошибка сервера")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// NewMWNoAuth используется, когда аутентификация выключена (локальный запуск):
+// все запросы выполняются от имени анонимного администратора.
+func NewMWNoAuth() func(http.Handler) http.Handler {
+	anonymous := auth.Principal{
+		Subject: "anonymous",
+		Role:    auth.RoleAdmin,
+		OrgID:   auth.DefaultOrgID,
+		Method:  auth.MethodNone,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), anonymous)))
+		})
+	}
+}
+
+// WriteError пишет ErrorResponse с кодом ошибки из спецификации.
+func WriteError(w http.ResponseWriter, status int, code openapi.ErrorResponseErrorCode, message string) {
+	var resp openapi.ErrorResponse
+	resp.Error.Code = code
+	resp.Error.Message = message
+	_ = transport.WriteJSON(w, status, resp)
+}
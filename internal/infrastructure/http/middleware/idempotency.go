@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/idempotency"
+	"pr-service/internal/infrastructure/http/openapi"
+	"pr-service/pkg/sl_logger/sl"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// NewMWIdempotency сохраняет ответы на запросы с заголовком Idempotency-Key к
+// перечисленным путям и воспроизводит их при повторе. Ключи изолированы по
+// организациям и principal-ам, поэтому middleware подключается после
+// аутентификации.
+// Ответы 5xx не сохраняются: такой запрос можно повторить с тем же ключом.
+// Незавершённый запрос держит ключ не дольше lease: если процесс упал, не
+// освободив ключ, повтор после аренды выполняется заново.
+func NewMWIdempotency(store idempotency.Store, ttl, lease time.Duration, log *slog.Logger, paths ...string) func(http.Handler) http.Handler {
+	log = log.With(slog.String("component", "middleware/idempotency"))
+
+	guarded := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		guarded[p] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if _, ok := guarded[r.URL.Path]; !ok || key == "" || r.Method != http.MethodPost {
+				next.ServeHTTP(w, r)
+				return
+			}
+			entry := log.With(slog.String("request_id", GetRequestID(r)), slog.String("idempotency_key", key))
+
+			if len(key) > maxIdempotencyKeyLength {
+				WriteError(w, http.StatusBadRequest, openapi.ErrorResponseErrorCode(http.StatusText(http.StatusBadRequest)), "слишком длинный Idempotency-Key")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				WriteError(w, http.StatusBadRequest, openapi.ErrorResponseErrorCode(http.StatusText(http.StatusBadRequest)), "не удалось прочитать тело запроса")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			principal, _ := auth.PrincipalFromContext(r.Context())
+			now := time.Now()
+			rec := idempotency.Record{
+				OrgID:       auth.OrgID(r.Context()),
+				Subject:     principal.Subject,
+				Key:         key,
+				RequestHash: idempotency.HashRequest(r.Method, r.URL.Path, body),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(ttl),
+				LockedUntil: now.Add(lease),
+			}
+
+			existing, reserved, err := store.IdempotencyReserve(r.Context(), rec)
+			if err != nil {
+				entry.Error("failed to reserve idempotency key", sl.Err(err))
+				WriteError(w, http.StatusInternalServerError, openapi.ErrorResponseErrorCode(http.StatusText(http.StatusInternalServerError)), "внутренняя ошибка сервера")
+				return
+			}
+			if !reserved {
+				replay(w, existing, rec.RequestHash, entry)
+				return
+			}
+
+			rw := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				// ответ не сохранён (5xx или паника) — освобождаем ключ для повтора
+				if err := store.IdempotencyRelease(context.WithoutCancel(r.Context()), rec); err != nil {
+					entry.Error("failed to release idempotency key", sl.Err(err))
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+
+			if rw.status >= http.StatusInternalServerError {
+				return
+			}
+			rec.StatusCode = rw.status
+			rec.ContentType = rw.Header().Get("Content-Type")
+			rec.ETag = rw.Header().Get("ETag")
+			rec.Body = rw.body.Bytes()
+			if err := store.IdempotencyComplete(context.WithoutCancel(r.Context()), rec); err != nil {
+				entry.Error("failed to store idempotent response", sl.Err(err))
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+func replay(w http.ResponseWriter, existing idempotency.Record, requestHash string, log *slog.Logger) {
+	err := idempotency.Check(existing, requestHash)
+	switch {
+	case errors.Is(err, idempotency.ErrKeyReused):
+		log.Warn("idempotency key reused with different request")
+		WriteError(w, http.StatusUnprocessableEntity, openapi.IDEMPOTENCYKEYREUSED, "Idempotency-Key уже использован для другого запроса")
+		return
+	case errors.Is(err, idempotency.ErrInProgress):
+		WriteError(w, http.StatusConflict, openapi.REQUESTINPROGRESS, "запрос с этим Idempotency-Key ещё выполняется")
+		return
+	}
+
+	if existing.ContentType != "" {
+		w.Header().Set("Content-Type", existing.ContentType)
+	}
+	if existing.ETag != "" {
+		w.Header().Set("ETag", existing.ETag)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(existing.StatusCode)
+	_, _ = w.Write(existing.Body)
+}
+
+// recordingWriter пишет ответ клиенту и одновременно копирует его для сохранения.
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	if !rw.wroteHeader {
+		rw.status = code
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 func NewMWLogger(log *slog.Logger) func(http.Handler) http.Handler {
@@ -19,6 +21,9 @@ func NewMWLogger(log *slog.Logger) func(http.Handler) http.Handler {
 				slog.String("user_agent", r.UserAgent()),
 				slog.String("request_id", GetRequestID(r)),
 			)
+			if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+				entry = entry.With(slog.String("trace_id", sc.TraceID().String()))
+			}
 
 			ww := NewWrapResponseWriter(w)
 			start := time.Now()
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HTTPObserver принимает результат обработки запроса.
+type HTTPObserver interface {
+	ObserveHTTP(route, method string, status int, duration time.Duration)
+}
+
+// NewMWMetrics собирает счётчики и гистограммы задержек по шаблону маршрута chi
+// (а не по сырому пути, чтобы не раздувать кардинальность меток).
+func NewMWMetrics(obs HTTPObserver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := NewWrapResponseWriter(w)
+			start := time.Now()
+
+			next.ServeHTTP(ww, r)
+
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			obs.ObserveHTTP(route, r.Method, ww.Status(), time.Since(start))
+		})
+	}
+}
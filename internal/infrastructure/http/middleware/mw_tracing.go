@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewMWTracing открывает серверный span на каждый запрос. Контекст трассировки
+// берётся из входящих заголовков W3C (traceparent/tracestate), X-Request-ID
+// пишется в атрибуты span, а trace id возвращается клиенту в X-Trace-ID.
+// Должен стоять после RequestID.
+func NewMWTracing() func(http.Handler) http.Handler {
+	tracer := otel.Tracer("pr-service/http")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", r.Method),
+					attribute.String("url.path", r.URL.Path),
+					attribute.String("request_id", GetRequestID(r)),
+				),
+			)
+			defer span.End()
+
+			if sc := span.SpanContext(); sc.HasTraceID() {
+				w.Header().Set("X-Trace-ID", sc.TraceID().String())
+			}
+
+			ww := NewWrapResponseWriter(w)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				span.SetName(r.Method + " " + rctx.RoutePattern())
+				span.SetAttributes(attribute.String("http.route", rctx.RoutePattern()))
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", ww.Status()))
+			if ww.Status() >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", ww.Status()))
+			}
+		})
+	}
+}
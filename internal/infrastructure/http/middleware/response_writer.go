@@ -30,3 +30,9 @@ func (rw *responseWriter) Status() int {
 func (rw *responseWriter) BytesWritten() int {
 	return rw.bytesWritten
 }
+
+// Unwrap нужен http.ResponseController: через него потоковые ответы
+// сбрасывают буфер и снимают WriteTimeout сервера.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
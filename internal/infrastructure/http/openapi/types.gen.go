@@ -7,14 +7,90 @@ import (
 	"time"
 )
 
+const (
+	ApiKeyAuthScopes = "ApiKeyAuth.Scopes"
+	BearerAuthScopes = "BearerAuth.Scopes"
+)
+
+// Defines values for AssignmentExclusionReason.
+const (
+	AtCapacity     AssignmentExclusionReason = "at_capacity"
+	Author         AssignmentExclusionReason = "author"
+	Inactive       AssignmentExclusionReason = "inactive"
+	InactiveInTeam AssignmentExclusionReason = "inactive_in_team"
+)
+
+// Defines values for AssignmentPickReason.
+const (
+	AssignmentPickReasonCodeOwner    AssignmentPickReason = "code_owner"
+	AssignmentPickReasonFallbackLead AssignmentPickReason = "fallback_lead"
+	AssignmentPickReasonLead         AssignmentPickReason = "lead"
+	AssignmentPickReasonPool         AssignmentPickReason = "pool"
+	AssignmentPickReasonSkill        AssignmentPickReason = "skill"
+)
+
+// Defines values for BulkCreatePullRequestStatus.
+const (
+	BulkCreatePullRequestStatusMERGED BulkCreatePullRequestStatus = "MERGED"
+	BulkCreatePullRequestStatusOPEN   BulkCreatePullRequestStatus = "OPEN"
+)
+
+// Defines values for BulkCreateResultStatus.
+const (
+	Created BulkCreateResultStatus = "created"
+	Failed  BulkCreateResultStatus = "failed"
+)
+
 // Defines values for ErrorResponseErrorCode.
 const (
-	NOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	NOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	NOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
-	PREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	PRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	TEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
+	ALLATCAPACITY        ErrorResponseErrorCode = "ALL_AT_CAPACITY"
+	CONCURRENTUPDATE     ErrorResponseErrorCode = "CONCURRENT_UPDATE"
+	FORBIDDEN            ErrorResponseErrorCode = "FORBIDDEN"
+	IDEMPOTENCYKEYREUSED ErrorResponseErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	NOCANDIDATE          ErrorResponseErrorCode = "NO_CANDIDATE"
+	NOTASSIGNED          ErrorResponseErrorCode = "NOT_ASSIGNED"
+	NOTFOUND             ErrorResponseErrorCode = "NOT_FOUND"
+	NOTTEAMLEAD          ErrorResponseErrorCode = "NOT_TEAM_LEAD"
+	PRECONDITIONFAILED   ErrorResponseErrorCode = "PRECONDITION_FAILED"
+	PREXISTS             ErrorResponseErrorCode = "PR_EXISTS"
+	PRMERGED             ErrorResponseErrorCode = "PR_MERGED"
+	REQUESTINPROGRESS    ErrorResponseErrorCode = "REQUEST_IN_PROGRESS"
+	TEAMCYCLE            ErrorResponseErrorCode = "TEAM_CYCLE"
+	TEAMEXISTS           ErrorResponseErrorCode = "TEAM_EXISTS"
+	UNAUTHORIZED         ErrorResponseErrorCode = "UNAUTHORIZED"
+	USERANONYMIZED       ErrorResponseErrorCode = "USER_ANONYMIZED"
+)
+
+// Defines values for HealthCheckStatus.
+const (
+	HealthCheckStatusDown HealthCheckStatus = "down"
+	HealthCheckStatusUp   HealthCheckStatus = "up"
+)
+
+// Defines values for HealthReportStatus.
+const (
+	HealthReportStatusDown HealthReportStatus = "down"
+	HealthReportStatusUp   HealthReportStatus = "up"
+)
+
+// Defines values for LeadRuleKind.
+const (
+	AuthorSeniority LeadRuleKind = "author_seniority"
+	Fallback        LeadRuleKind = "fallback"
+	Label           LeadRuleKind = "label"
+	NamePrefix      LeadRuleKind = "name_prefix"
+)
+
+// Defines values for MembershipRole.
+const (
+	MembershipRoleLead   MembershipRole = "lead"
+	MembershipRoleMember MembershipRole = "member"
+)
+
+// Defines values for OpenReviews.
+const (
+	Keep     OpenReviews = "keep"
+	Reassign OpenReviews = "reassign"
 )
 
 // Defines values for PullRequestStatus.
@@ -25,10 +101,246 @@ const (
 
 // Defines values for PullRequestShortStatus.
 const (
-	PullRequestShortStatusMERGED PullRequestShortStatus = "MERGED"
-	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
+	MERGED PullRequestShortStatus = "MERGED"
+	OPEN   PullRequestShortStatus = "OPEN"
+)
+
+// Defines values for ReviewerChangeEvent.
+const (
+	Reassigned ReviewerChangeEvent = "reassigned"
+	Released   ReviewerChangeEvent = "released"
+)
+
+// Defines values for Role.
+const (
+	Admin    Role = "admin"
+	Bot      Role = "bot"
+	Member   Role = "member"
+	TeamLead Role = "team_lead"
+)
+
+// Defines values for SelectionMode.
+const (
+	Random SelectionMode = "random"
+	Spread SelectionMode = "spread"
+)
+
+// Defines values for Seniority.
+const (
+	SeniorityJunior Seniority = "junior"
+	SeniorityMiddle Seniority = "middle"
+	SenioritySenior Seniority = "senior"
+)
+
+// Defines values for SkillLevel.
+const (
+	Basic        SkillLevel = "basic"
+	Expert       SkillLevel = "expert"
+	Intermediate SkillLevel = "intermediate"
+)
+
+// Defines values for ExportFormatQuery.
+const (
+	ExportFormatQueryCsv    ExportFormatQuery = "csv"
+	ExportFormatQueryNdjson ExportFormatQuery = "ndjson"
+)
+
+// Defines values for GetExportPullRequestsParamsFormat.
+const (
+	GetExportPullRequestsParamsFormatCsv    GetExportPullRequestsParamsFormat = "csv"
+	GetExportPullRequestsParamsFormatNdjson GetExportPullRequestsParamsFormat = "ndjson"
+)
+
+// Defines values for GetExportTeamsParamsFormat.
+const (
+	GetExportTeamsParamsFormatCsv    GetExportTeamsParamsFormat = "csv"
+	GetExportTeamsParamsFormatNdjson GetExportTeamsParamsFormat = "ndjson"
+)
+
+// Defines values for GetExportUsersParamsFormat.
+const (
+	GetExportUsersParamsFormatCsv    GetExportUsersParamsFormat = "csv"
+	GetExportUsersParamsFormatNdjson GetExportUsersParamsFormat = "ndjson"
+)
+
+// Defines values for PostUsersUpdateJSONBodySeniority.
+const (
+	PostUsersUpdateJSONBodySeniorityEmpty  PostUsersUpdateJSONBodySeniority = ""
+	PostUsersUpdateJSONBodySeniorityJunior PostUsersUpdateJSONBodySeniority = "junior"
+	PostUsersUpdateJSONBodySeniorityMiddle PostUsersUpdateJSONBodySeniority = "middle"
+	PostUsersUpdateJSONBodySenioritySenior PostUsersUpdateJSONBodySeniority = "senior"
 )
 
+// APIKey defines model for APIKey.
+type APIKey struct {
+	CreatedAt time.Time  `json:"created_at"`
+	KeyId     string     `json:"key_id"`
+	Name      string     `json:"name"`
+	OrgId     string     `json:"org_id"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	Role      Role       `json:"role"`
+
+	// Subject user_id пользователя или имя бота, от имени которого действует ключ
+	Subject  string  `json:"subject"`
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// AssignmentExclusion defines model for AssignmentExclusion.
+type AssignmentExclusion struct {
+	// Reason `author` — автор PR; `inactive` — пользователь неактивен; `inactive_in_team` —
+	// не участвует в ревью команды; `at_capacity` — исчерпан лимит открытых ревью.
+	Reason   AssignmentExclusionReason `json:"reason"`
+	TeamName string                    `json:"team_name"`
+	UserId   string                    `json:"user_id"`
+}
+
+// AssignmentExclusionReason `author` — автор PR; `inactive` — пользователь неактивен; `inactive_in_team` —
+// не участвует в ревью команды; `at_capacity` — исчерпан лимит открытых ревью.
+type AssignmentExclusionReason string
+
+// AssignmentExplanation defines model for AssignmentExplanation.
+type AssignmentExplanation struct {
+	// AssignedAt Момент подбора, от которого считались рабочее время и окно прошлых пар
+	AssignedAt time.Time `json:"assigned_at"`
+
+	// Candidates Свободные кандидаты в порядке режима подбора, кроме лидов и владельцев кода
+	Candidates []string `json:"candidates"`
+
+	// Changes Замены ревьюверов после создания PR
+	Changes    []ReviewerChange      `json:"changes"`
+	CreatedAt  time.Time             `json:"created_at"`
+	Exclusions []AssignmentExclusion `json:"exclusions"`
+
+	// Picks Назначенные ревьюверы в порядке выбора
+	Picks []AssignmentPick `json:"picks"`
+
+	// PoolTeam Команда, из которой брались кандидаты; отличается от `team_name`, если подбор ушёл в родительскую команду
+	PoolTeam           string `json:"pool_team"`
+	PreferWorkingHours bool   `json:"prefer_working_hours"`
+	PullRequestId      string `json:"pull_request_id"`
+
+	// Seed Зерно случайного подбора; с ним, `assigned_at` и теми же данными подбор повторяется
+	Seed int64 `json:"seed"`
+
+	// SelectionMode Подбор ревьюверов при создании PR: `random` — случайно (по умолчанию);
+	// `spread` — реже выбирать тех, кто недавно ревьюил автора.
+	SelectionMode SelectionMode `json:"selection_mode"`
+
+	// TeamName Команда PR
+	TeamName string `json:"team_name"`
+}
+
+// AssignmentPick defines model for AssignmentPick.
+type AssignmentPick struct {
+	// Reason `lead` — совпало правило лида; `fallback_lead` — больше некого назначить;
+	// `code_owner` — владелец изменённых файлов; `skill` — покрывает требуемый навык;
+	// `pool` — следующий по порядку кандидат.
+	Reason AssignmentPickReason `json:"reason"`
+	UserId string               `json:"user_id"`
+}
+
+// AssignmentPickReason `lead` — совпало правило лида; `fallback_lead` — больше некого назначить;
+// `code_owner` — владелец изменённых файлов; `skill` — покрывает требуемый навык;
+// `pool` — следующий по порядку кандидат.
+type AssignmentPickReason string
+
+// AssignmentPreview defines model for AssignmentPreview.
+type AssignmentPreview struct {
+	// Candidates Свободные кандидаты в порядке режима подбора, кроме лидов и владельцев кода
+	Candidates []string              `json:"candidates"`
+	Exclusions []AssignmentExclusion `json:"exclusions"`
+
+	// Picks Ревьюверы, которые были бы назначены, в порядке выбора
+	Picks []AssignmentPick `json:"picks"`
+
+	// PoolTeam Команда, из которой брались кандидаты; отличается от `team_name`, если подбор ушёл в родительскую команду
+	PoolTeam           string `json:"pool_team"`
+	PreferWorkingHours bool   `json:"prefer_working_hours"`
+
+	// SelectionMode Подбор ревьюверов при создании PR: `random` — случайно (по умолчанию);
+	// `spread` — реже выбирать тех, кто недавно ревьюил автора.
+	SelectionMode SelectionMode `json:"selection_mode"`
+
+	// TeamName Команда PR
+	TeamName string `json:"team_name"`
+
+	// Warnings Например, какие навыки из `required_skills` не покрыты
+	Warnings *[]string `json:"warnings,omitempty"`
+}
+
+// BulkCreatePullRequest defines model for BulkCreatePullRequest.
+type BulkCreatePullRequest struct {
+	AuthorId     string    `json:"author_id"`
+	ChangedFiles *[]string `json:"changed_files,omitempty"`
+
+	// CreatedAt Исходный момент создания; обязателен для `MERGED`, иначе по умолчанию — момент запроса
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Labels    *[]string  `json:"labels,omitempty"`
+
+	// MergedAt Обязателен для `MERGED`, не раньше `created_at`; для `OPEN` не задаётся
+	MergedAt        *time.Time `json:"merged_at,omitempty"`
+	PullRequestId   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	RequiredSkills  *[]string  `json:"required_skills,omitempty"`
+
+	// Status По умолчанию `OPEN`
+	Status   *BulkCreatePullRequestStatus `json:"status,omitempty"`
+	TeamName *string                      `json:"team_name,omitempty"`
+}
+
+// BulkCreatePullRequestStatus По умолчанию `OPEN`
+type BulkCreatePullRequestStatus string
+
+// BulkCreateResult defines model for BulkCreateResult.
+type BulkCreateResult struct {
+	Error *struct {
+		// Code `INVALID_ITEM` — PR не прошёл проверку; `PR_EXISTS` — PR уже есть или повторяется
+		// в пакете; `NOT_FOUND` — автор или команда не найдены; `NOT_TEAM_MEMBER` — автор
+		// не состоит в `team_name`; `NO_CANDIDATE`, `ALL_AT_CAPACITY` — как при создании PR;
+		// `INTERNAL` — сбой при записи, PR можно отправить повторно.
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+	Pr            *PullRequest           `json:"pr,omitempty"`
+	PullRequestId string                 `json:"pull_request_id"`
+	Status        BulkCreateResultStatus `json:"status"`
+}
+
+// BulkCreateResultStatus defines model for BulkCreateResult.Status.
+type BulkCreateResultStatus string
+
+// CodeownersError defines model for CodeownersError.
+type CodeownersError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// CodeownersReport defines model for CodeownersReport.
+type CodeownersReport struct {
+	Errors   []CodeownersError `json:"errors"`
+	Rules    []CodeownersRule  `json:"rules"`
+	TeamName string            `json:"team_name"`
+
+	// Valid Файл без ошибок; только такой сохраняется
+	Valid bool `json:"valid"`
+}
+
+// CodeownersRule defines model for CodeownersRule.
+type CodeownersRule struct {
+	Line    int      `json:"line"`
+	Pattern string   `json:"pattern"`
+	Teams   []string `json:"teams"`
+	Users   []string `json:"users"`
+}
+
+// CodeownersUpload defines model for CodeownersUpload.
+type CodeownersUpload struct {
+	// Content Текст в формате CODEOWNERS: шаблон пути и владельцы через пробел.
+	// `@user_id` — пользователь, `@org/team_name` — команда (учитывается часть после `/`).
+	Content  string `json:"content"`
+	TeamName string `json:"team_name"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Error struct {
@@ -40,64 +352,400 @@ type ErrorResponse struct {
 // ErrorResponseErrorCode defines model for ErrorResponse.Error.Code.
 type ErrorResponseErrorCode string
 
+// HealthCheck defines model for HealthCheck.
+type HealthCheck struct {
+	Details *map[string]interface{} `json:"details,omitempty"`
+	Error   *string                 `json:"error,omitempty"`
+	Status  HealthCheckStatus       `json:"status"`
+}
+
+// HealthCheckStatus defines model for HealthCheck.Status.
+type HealthCheckStatus string
+
+// HealthReport defines model for HealthReport.
+type HealthReport struct {
+	Checks *map[string]HealthCheck `json:"checks,omitempty"`
+	Status HealthReportStatus      `json:"status"`
+}
+
+// HealthReportStatus defines model for HealthReport.Status.
+type HealthReportStatus string
+
+// LeadRule defines model for LeadRule.
+type LeadRule struct {
+	// Kind `name_prefix` — название PR начинается с `value`; `label` — у PR есть метка `value`;
+	// `author_seniority` — грейд автора равен `value`; `fallback` — в команде и выше
+	// по иерархии больше некого назначить (`value` не нужен).
+	Kind  LeadRuleKind `json:"kind"`
+	Value *string      `json:"value,omitempty"`
+}
+
+// LeadRuleKind `name_prefix` — название PR начинается с `value`; `label` — у PR есть метка `value`;
+// `author_seniority` — грейд автора равен `value`; `fallback` — в команде и выше
+// по иерархии больше некого назначить (`value` не нужен).
+type LeadRuleKind string
+
+// MembershipRole Роль в команде. Если не указана при добавлении — `member` для новых участников, у существующих не меняется.
+type MembershipRole string
+
+// OpenReviews Что делать с открытыми ревью участников, которые больше не состоят в команде автора PR:
+// `keep` — оставить как есть; `reassign` — заменить активным участником команды автора,
+// а если замены нет — снять ревьювера.
+type OpenReviews string
+
+// Organization defines model for Organization.
+type Organization struct {
+	CreatedAt time.Time `json:"created_at"`
+	Name      string    `json:"name"`
+	OrgId     string    `json:"org_id"`
+}
+
+// Pairing defines model for Pairing.
+type Pairing struct {
+	// Count Сколько раз ревьювер назначался на PR автора за 90 дней
+	Count      int       `json:"count"`
+	LastAt     time.Time `json:"last_at"`
+	ReviewerId string    `json:"reviewer_id"`
+}
+
 // PullRequest defines model for PullRequest.
 type PullRequest struct {
 	// AssignedReviewers user_id назначенных ревьюверов (0..2)
-	AssignedReviewers []string          `json:"assigned_reviewers"`
-	AuthorId          string            `json:"author_id"`
-	CreatedAt         *time.Time        `json:"createdAt"`
-	MergedAt          *time.Time        `json:"mergedAt"`
-	PullRequestId     string            `json:"pull_request_id"`
-	PullRequestName   string            `json:"pull_request_name"`
-	Status            PullRequestStatus `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	AuthorId          string     `json:"author_id"`
+	CreatedAt         *time.Time `json:"createdAt"`
+	Labels            *[]string  `json:"labels,omitempty"`
+	MergedAt          *time.Time `json:"mergedAt"`
+	PullRequestId     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+
+	// ReviewAssignedAt Только в `/users/getReview` — когда пользователь назначен ревьювером PR
+	ReviewAssignedAt *time.Time `json:"review_assigned_at,omitempty"`
+
+	// ReviewDueAt Только в `/users/getReview`, у открытых PR — срок ревью: `review.sla` рабочего
+	// времени ревьювера от момента назначения
+	ReviewDueAt *time.Time        `json:"review_due_at,omitempty"`
+	Status      PullRequestStatus `json:"status"`
+
+	// TeamName Команда, из которой назначены ревьюверы
+	TeamName *string `json:"team_name"`
+
+	// Version Увеличивается при каждом изменении PR; совпадает со значением ETag
+	Version int64 `json:"version"`
+
+	// Warnings Только в ответе на создание — например, какие навыки из `required_skills` не покрыты
+	Warnings *[]string `json:"warnings,omitempty"`
 }
 
 // PullRequestStatus defines model for PullRequest.Status.
 type PullRequestStatus string
 
+// PullRequestCreate defines model for PullRequestCreate.
+type PullRequestCreate struct {
+	AuthorId string `json:"author_id"`
+
+	// ChangedFiles Изменённые файлы; их владельцы из CODEOWNERS команды назначаются раньше остальных
+	ChangedFiles    *[]string `json:"changed_files,omitempty"`
+	Labels          *[]string `json:"labels,omitempty"`
+	PullRequestId   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+
+	// RequiredSkills Навыки, каждый из которых по возможности должен быть хотя бы у одного ревьювера.
+	// Непокрытые навыки перечисляются в `warnings`
+	RequiredSkills *[]string `json:"required_skills,omitempty"`
+	TeamName       *string   `json:"team_name,omitempty"`
+}
+
 // PullRequestShort defines model for PullRequestShort.
 type PullRequestShort struct {
-	AuthorId        string                 `json:"author_id"`
-	PullRequestId   string                 `json:"pull_request_id"`
-	PullRequestName string                 `json:"pull_request_name"`
-	Status          PullRequestShortStatus `json:"status"`
+	AuthorId         string                 `json:"author_id"`
+	PullRequestId    string                 `json:"pull_request_id"`
+	PullRequestName  string                 `json:"pull_request_name"`
+	ReviewAssignedAt *time.Time             `json:"review_assigned_at,omitempty"`
+	ReviewDueAt      *time.Time             `json:"review_due_at,omitempty"`
+	Status           PullRequestShortStatus `json:"status"`
 }
 
 // PullRequestShortStatus defines model for PullRequestShort.Status.
 type PullRequestShortStatus string
 
+// Reassignment defines model for Reassignment.
+type Reassignment struct {
+	// NewUserId Новый ревьювер; отсутствует, если замены не нашлось и ревьювер снят
+	NewUserId     *string `json:"new_user_id"`
+	OldUserId     string  `json:"old_user_id"`
+	PullRequestId string  `json:"pull_request_id"`
+}
+
+// ReviewerChange defines model for ReviewerChange.
+type ReviewerChange struct {
+	At time.Time `json:"at"`
+
+	// Event `reassigned` — запрос на переназначение; `released` — ревьювер ушёл из команды или анонимизирован
+	Event ReviewerChangeEvent `json:"event"`
+
+	// NewUserId Нет, если замену найти не удалось
+	NewUserId *string `json:"new_user_id,omitempty"`
+	OldUserId string  `json:"old_user_id"`
+}
+
+// ReviewerChangeEvent `reassigned` — запрос на переназначение; `released` — ревьювер ушёл из команды или анонимизирован
+type ReviewerChangeEvent string
+
+// Role defines model for Role.
+type Role string
+
+// SelectionMode Подбор ревьюверов при создании PR: `random` — случайно (по умолчанию);
+// `spread` — реже выбирать тех, кто недавно ревьюил автора.
+type SelectionMode string
+
+// Seniority defines model for Seniority.
+type Seniority string
+
+// Skill defines model for Skill.
+type Skill struct {
+	Level SkillLevel `json:"level"`
+
+	// Tag Навык, например `go`, `sql`, `frontend`; хранится в нижнем регистре
+	Tag string `json:"tag"`
+}
+
+// SkillLevel defines model for SkillLevel.
+type SkillLevel string
+
 // Team defines model for Team.
 type Team struct {
-	Members  []TeamMember `json:"members"`
-	TeamName string       `json:"team_name"`
+	// MaxOpenReviews Сколько открытых PR участник может ревьюить одновременно, если у него нет
+	// личного лимита; отсутствует — без ограничения
+	MaxOpenReviews *int         `json:"max_open_reviews,omitempty"`
+	Members        []TeamMember `json:"members"`
+
+	// ParentTeam Родительская команда (отдел); у корневых команд отсутствует
+	ParentTeam *string `json:"parent_team"`
+
+	// PreferWorkingHours При создании PR раньше назначаются участники, у которых сейчас рабочее время
+	PreferWorkingHours *bool `json:"prefer_working_hours,omitempty"`
+
+	// SelectionMode Подбор ревьюверов при создании PR: `random` — случайно (по умолчанию);
+	// `spread` — реже выбирать тех, кто недавно ревьюил автора.
+	SelectionMode *SelectionMode `json:"selection_mode,omitempty"`
+	TeamName      string         `json:"team_name"`
+}
+
+// TeamChangeResult defines model for TeamChangeResult.
+type TeamChangeResult struct {
+	Reassignments []Reassignment `json:"reassignments"`
+	Team          Team           `json:"team"`
 }
 
 // TeamMember defines model for TeamMember.
 type TeamMember struct {
-	IsActive bool   `json:"is_active"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+	// IsActive В запросах — общий флаг активности пользователя. В ответах — участвует ли
+	// пользователь в ревью этой команды (активен и он сам, и его членство в команде).
+	IsActive bool `json:"is_active"`
+
+	// LeadRules Только в ответах и только у лидов — правила из `/team/setLeadRules`
+	LeadRules *[]LeadRule `json:"lead_rules,omitempty"`
+
+	// Role Роль в команде. Если не указана при добавлении — `member` для новых участников, у существующих не меняется.
+	Role     *MembershipRole `json:"role,omitempty"`
+	UserId   string          `json:"user_id"`
+	Username string          `json:"username"`
+}
+
+// TeamStatsRow defines model for TeamStatsRow.
+type TeamStatsRow struct {
+	// ActiveMembers Участники, активные и в целом, и в этой команде
+	ActiveMembers      int `json:"active_members"`
+	Members            int `json:"members"`
+	MergedPullRequests int `json:"merged_pull_requests"`
+	OpenPullRequests   int `json:"open_pull_requests"`
+
+	// OpenReviews Назначения ревьюверов на открытые PR команды
+	OpenReviews int    `json:"open_reviews"`
+	TeamName    string `json:"team_name"`
+}
+
+// TeamTreeNode defines model for TeamTreeNode.
+type TeamTreeNode struct {
+	Children []TeamTreeNode `json:"children"`
+
+	// MembersCount Число участников самой команды, без подкоманд
+	MembersCount int    `json:"members_count"`
+	TeamName     string `json:"team_name"`
 }
 
 // User defines model for User.
 type User struct {
-	IsActive bool   `json:"is_active"`
-	TeamName string `json:"team_name"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+	// AnonymizedAt Момент анонимизации; имя пользователя к этому моменту стёрто
+	AnonymizedAt *time.Time `json:"anonymized_at"`
+	IsActive     bool       `json:"is_active"`
+
+	// MaxOpenReviews Личный лимит открытых ревью; отсутствует — действует лимит команды
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty"`
+
+	// Seniority Грейд; критерий правила лида `author_seniority`
+	Seniority *Seniority `json:"seniority"`
+	Skills    *[]Skill   `json:"skills,omitempty"`
+
+	// TeamName Основная команда — пул ревьюверов по умолчанию для PR пользователя
+	TeamName *string `json:"team_name"`
+
+	// Teams Все команды пользователя, включая основную
+	Teams    *[]string `json:"teams,omitempty"`
+	UserId   string    `json:"user_id"`
+	Username string    `json:"username"`
+
+	// WorkingHours Рабочее время; не задано — пользователь считается доступным всегда
+	WorkingHours *WorkingHours `json:"working_hours"`
+}
+
+// UserChangeResult defines model for UserChangeResult.
+type UserChangeResult struct {
+	Reassignments []Reassignment `json:"reassignments"`
+	User          User           `json:"user"`
 }
 
+// WorkingHours defines model for WorkingHours.
+type WorkingHours struct {
+	// Days Рабочие дни, 1 — понедельник, 7 — воскресенье
+	Days []int `json:"days"`
+
+	// End Конец рабочего дня, позже начала
+	End string `json:"end"`
+
+	// Start Начало рабочего дня по местному времени
+	Start string `json:"start"`
+
+	// Timezone Часовой пояс IANA
+	Timezone string `json:"timezone"`
+}
+
+// ExportFormatQuery defines model for ExportFormatQuery.
+type ExportFormatQuery string
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// PullRequestIdQuery defines model for PullRequestIdQuery.
+type PullRequestIdQuery = string
+
 // TeamNameQuery defines model for TeamNameQuery.
 type TeamNameQuery = string
 
 // UserIdQuery defines model for UserIdQuery.
 type UserIdQuery = string
 
-// PostPullRequestCreateJSONBody defines parameters for PostPullRequestCreate.
-type PostPullRequestCreateJSONBody struct {
-	AuthorId        string `json:"author_id"`
-	PullRequestId   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
+// Forbidden defines model for Forbidden.
+type Forbidden = ErrorResponse
+
+// IdempotencyKeyReused defines model for IdempotencyKeyReused.
+type IdempotencyKeyReused = ErrorResponse
+
+// PreconditionFailed defines model for PreconditionFailed.
+type PreconditionFailed = ErrorResponse
+
+// RequestInProgress defines model for RequestInProgress.
+type RequestInProgress = ErrorResponse
+
+// PostAdminApiKeysCreateJSONBody defines parameters for PostAdminApiKeysCreate.
+type PostAdminApiKeysCreateJSONBody struct {
+	Name string `json:"name"`
+
+	// OrgId Организация ключа. По умолчанию — организация вызывающего; чужую может указать только администратор платформы
+	OrgId    *string `json:"org_id,omitempty"`
+	Role     Role    `json:"role"`
+	Subject  string  `json:"subject"`
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// PostAdminApiKeysRevokeJSONBody defines parameters for PostAdminApiKeysRevoke.
+type PostAdminApiKeysRevokeJSONBody struct {
+	KeyId string `json:"key_id"`
+}
+
+// PostAdminOrganizationsCreateJSONBody defines parameters for PostAdminOrganizationsCreate.
+type PostAdminOrganizationsCreateJSONBody struct {
+	Name  string `json:"name"`
+	OrgId string `json:"org_id"`
+}
+
+// GetExportPullRequestsParams defines parameters for GetExportPullRequests.
+type GetExportPullRequestsParams struct {
+	// Format `csv` — заголовок и строка на объект, списки через `;`;
+	// `ndjson` — JSON-объект на строку
+	Format *GetExportPullRequestsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Status `OPEN` или `MERGED`
+	Status   *string `form:"status,omitempty" json:"status,omitempty"`
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+	AuthorId *string `form:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// CreatedFrom Созданные не раньше
+	CreatedFrom *time.Time `form:"created_from,omitempty" json:"created_from,omitempty"`
+
+	// CreatedTo Созданные раньше (не включительно)
+	CreatedTo *time.Time `form:"created_to,omitempty" json:"created_to,omitempty"`
+}
+
+// GetExportPullRequestsParamsFormat defines parameters for GetExportPullRequests.
+type GetExportPullRequestsParamsFormat string
+
+// GetExportTeamsParams defines parameters for GetExportTeams.
+type GetExportTeamsParams struct {
+	// Format `csv` — заголовок и строка на объект, списки через `;`;
+	// `ndjson` — JSON-объект на строку
+	Format *GetExportTeamsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// TeamName Корень поддерева; без параметра выгружаются все команды организации
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+}
+
+// GetExportTeamsParamsFormat defines parameters for GetExportTeams.
+type GetExportTeamsParamsFormat string
+
+// GetExportUsersParams defines parameters for GetExportUsers.
+type GetExportUsersParams struct {
+	// Format `csv` — заголовок и строка на объект, списки через `;`;
+	// `ndjson` — JSON-объект на строку
+	Format *GetExportUsersParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// TeamName Только участники команды
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+	IsActive *bool   `form:"is_active,omitempty" json:"is_active,omitempty"`
+
+	// After Начать после этого user_id
+	After *string `form:"after,omitempty" json:"after,omitempty"`
+}
+
+// GetExportUsersParamsFormat defines parameters for GetExportUsers.
+type GetExportUsersParamsFormat string
+
+// PostPullRequestBulkCreateJSONBody defines parameters for PostPullRequestBulkCreate.
+type PostPullRequestBulkCreateJSONBody struct {
+	PullRequests []BulkCreatePullRequest `json:"pull_requests"`
+}
+
+// PostPullRequestBulkCreateParams defines parameters for PostPullRequestBulkCreate.
+type PostPullRequestBulkCreateParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostPullRequestCreateParams defines parameters for PostPullRequestCreate.
+type PostPullRequestCreateParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetPullRequestExplainAssignmentParams defines parameters for GetPullRequestExplainAssignment.
+type GetPullRequestExplainAssignmentParams struct {
+	PullRequestId PullRequestIdQuery `form:"pull_request_id" json:"pull_request_id"`
 }
 
 // PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
@@ -105,41 +753,372 @@ type PostPullRequestMergeJSONBody struct {
 	PullRequestId string `json:"pull_request_id"`
 }
 
+// PostPullRequestMergeParams defines parameters for PostPullRequestMerge.
+type PostPullRequestMergeParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+
+	// IfMatch ETag PR, полученный ранее. Если версия PR изменилась, запрос отклоняется с 412.
+	// `*` и отсутствие заголовка — без проверки.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
 // PostPullRequestReassignJSONBody defines parameters for PostPullRequestReassign.
 type PostPullRequestReassignJSONBody struct {
 	OldUserId     string `json:"old_user_id"`
 	PullRequestId string `json:"pull_request_id"`
 }
 
+// PostPullRequestReassignParams defines parameters for PostPullRequestReassign.
+type PostPullRequestReassignParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+
+	// IfMatch ETag PR, полученный ранее. Если версия PR изменилась, запрос отклоняется с 412.
+	// `*` и отсутствие заголовка — без проверки.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// DeleteTeamParams defines parameters for DeleteTeam.
+type DeleteTeamParams struct {
+	// TeamName Уникальное имя команды
+	TeamName    TeamNameQuery `form:"team_name" json:"team_name"`
+	OpenReviews OpenReviews   `form:"open_reviews" json:"open_reviews"`
+}
+
+// PostTeamAddParams defines parameters for PostTeamAdd.
+type PostTeamAddParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostTeamAddMembersJSONBody defines parameters for PostTeamAddMembers.
+type PostTeamAddMembersJSONBody struct {
+	Members []TeamMember `json:"members"`
+
+	// OpenReviews Что делать с открытыми ревью участников, которые больше не состоят в команде автора PR:
+	// `keep` — оставить как есть; `reassign` — заменить активным участником команды автора,
+	// а если замены нет — снять ревьювера.
+	OpenReviews *OpenReviews `json:"open_reviews,omitempty"`
+	TeamName    string       `json:"team_name"`
+}
+
+// PostTeamAddMembersParams defines parameters for PostTeamAddMembers.
+type PostTeamAddMembersParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
 // GetTeamGetParams defines parameters for GetTeamGet.
 type GetTeamGetParams struct {
 	// TeamName Уникальное имя команды
 	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
 }
 
+// PostTeamRemoveMembersJSONBody defines parameters for PostTeamRemoveMembers.
+type PostTeamRemoveMembersJSONBody struct {
+	// OpenReviews Что делать с открытыми ревью участников, которые больше не состоят в команде автора PR:
+	// `keep` — оставить как есть; `reassign` — заменить активным участником команды автора,
+	// а если замены нет — снять ревьювера.
+	OpenReviews OpenReviews `json:"open_reviews"`
+	TeamName    string      `json:"team_name"`
+	UserIds     []string    `json:"user_ids"`
+}
+
+// PostTeamRemoveMembersParams defines parameters for PostTeamRemoveMembers.
+type PostTeamRemoveMembersParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostTeamRenameJSONBody defines parameters for PostTeamRename.
+type PostTeamRenameJSONBody struct {
+	NewTeamName string `json:"new_team_name"`
+	TeamName    string `json:"team_name"`
+}
+
+// PostTeamRenameParams defines parameters for PostTeamRename.
+type PostTeamRenameParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostTeamSetLeadRulesJSONBody defines parameters for PostTeamSetLeadRules.
+type PostTeamSetLeadRulesJSONBody struct {
+	Rules    []LeadRule `json:"rules"`
+	TeamName string     `json:"team_name"`
+	UserId   string     `json:"user_id"`
+}
+
+// PostTeamSetLeadRulesParams defines parameters for PostTeamSetLeadRules.
+type PostTeamSetLeadRulesParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostTeamSetMaxOpenReviewsJSONBody defines parameters for PostTeamSetMaxOpenReviews.
+type PostTeamSetMaxOpenReviewsJSONBody struct {
+	// MaxOpenReviews 0 снимает лимит
+	MaxOpenReviews int    `json:"max_open_reviews"`
+	TeamName       string `json:"team_name"`
+}
+
+// PostTeamSetMaxOpenReviewsParams defines parameters for PostTeamSetMaxOpenReviews.
+type PostTeamSetMaxOpenReviewsParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostTeamSetMemberActiveJSONBody defines parameters for PostTeamSetMemberActive.
+type PostTeamSetMemberActiveJSONBody struct {
+	IsActive bool   `json:"is_active"`
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+}
+
+// PostTeamSetMemberActiveParams defines parameters for PostTeamSetMemberActive.
+type PostTeamSetMemberActiveParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostTeamSetParentJSONBody defines parameters for PostTeamSetParent.
+type PostTeamSetParentJSONBody struct {
+	ParentTeam *string `json:"parent_team"`
+	TeamName   string  `json:"team_name"`
+}
+
+// PostTeamSetParentParams defines parameters for PostTeamSetParent.
+type PostTeamSetParentParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostTeamSetSelectionModeJSONBody defines parameters for PostTeamSetSelectionMode.
+type PostTeamSetSelectionModeJSONBody struct {
+	PreferWorkingHours *bool `json:"prefer_working_hours,omitempty"`
+
+	// SelectionMode Подбор ревьюверов при создании PR: `random` — случайно (по умолчанию);
+	// `spread` — реже выбирать тех, кто недавно ревьюил автора.
+	SelectionMode *SelectionMode `json:"selection_mode,omitempty"`
+	TeamName      string         `json:"team_name"`
+}
+
+// PostTeamSetSelectionModeParams defines parameters for PostTeamSetSelectionMode.
+type PostTeamSetSelectionModeParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetTeamStatsParams defines parameters for GetTeamStats.
+type GetTeamStatsParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// GetTeamTreeParams defines parameters for GetTeamTree.
+type GetTeamTreeParams struct {
+	// TeamName Корень поддерева; без параметра возвращаются все деревья организации
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+}
+
+// PostTeamUploadCodeownersParams defines parameters for PostTeamUploadCodeowners.
+type PostTeamUploadCodeownersParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostUsersAnonymizeJSONBody defines parameters for PostUsersAnonymize.
+type PostUsersAnonymizeJSONBody struct {
+	// OpenReviews Что делать с открытыми ревью участников, которые больше не состоят в команде автора PR:
+	// `keep` — оставить как есть; `reassign` — заменить активным участником команды автора,
+	// а если замены нет — снять ревьювера.
+	OpenReviews OpenReviews `json:"open_reviews"`
+	UserId      string      `json:"user_id"`
+}
+
+// PostUsersAnonymizeParams defines parameters for PostUsersAnonymize.
+type PostUsersAnonymizeParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetUsersGetParams defines parameters for GetUsersGet.
+type GetUsersGetParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
 // GetUsersGetReviewParams defines parameters for GetUsersGetReview.
 type GetUsersGetReviewParams struct {
 	// UserId Идентификатор пользователя
 	UserId UserIdQuery `form:"user_id" json:"user_id"`
 }
 
+// GetUsersListParams defines parameters for GetUsersList.
+type GetUsersListParams struct {
+	// TeamName Только участники команды
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+	IsActive *bool   `form:"is_active,omitempty" json:"is_active,omitempty"`
+	Limit    *int    `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// After Курсор — `next_after` из предыдущей страницы
+	After *string `form:"after,omitempty" json:"after,omitempty"`
+}
+
+// GetUsersPairingsParams defines parameters for GetUsersPairings.
+type GetUsersPairingsParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
 // PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
 type PostUsersSetIsActiveJSONBody struct {
 	IsActive bool   `json:"is_active"`
 	UserId   string `json:"user_id"`
 }
 
+// PostUsersSetIsActiveParams defines parameters for PostUsersSetIsActive.
+type PostUsersSetIsActiveParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostUsersSetSkillsJSONBody defines parameters for PostUsersSetSkills.
+type PostUsersSetSkillsJSONBody struct {
+	Skills []Skill `json:"skills"`
+	UserId string  `json:"user_id"`
+}
+
+// PostUsersSetSkillsParams defines parameters for PostUsersSetSkills.
+type PostUsersSetSkillsParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostUsersSetWorkingHoursJSONBody defines parameters for PostUsersSetWorkingHours.
+type PostUsersSetWorkingHoursJSONBody struct {
+	UserId       string        `json:"user_id"`
+	WorkingHours *WorkingHours `json:"working_hours"`
+}
+
+// PostUsersSetWorkingHoursParams defines parameters for PostUsersSetWorkingHours.
+type PostUsersSetWorkingHoursParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostUsersUpdateJSONBody defines parameters for PostUsersUpdate.
+type PostUsersUpdateJSONBody struct {
+	// MaxOpenReviews Личный лимит открытых ревью; 0 снимает его
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty"`
+
+	// OpenReviews Что делать с открытыми ревью участников, которые больше не состоят в команде автора PR:
+	// `keep` — оставить как есть; `reassign` — заменить активным участником команды автора,
+	// а если замены нет — снять ревьювера.
+	OpenReviews *OpenReviews `json:"open_reviews,omitempty"`
+
+	// Seniority Пустая строка снимает грейд
+	Seniority *PostUsersUpdateJSONBodySeniority `json:"seniority,omitempty"`
+	TeamName  *string                           `json:"team_name,omitempty"`
+	UserId    string                            `json:"user_id"`
+	Username  *string                           `json:"username,omitempty"`
+}
+
+// PostUsersUpdateParams defines parameters for PostUsersUpdate.
+type PostUsersUpdateParams struct {
+	// IdempotencyKey Ключ идемпотентности. Ответ сохраняется на `idempotency.ttl`; повтор с тем же ключом
+	// и телом возвращает сохранённый ответ с заголовком `Idempotent-Replayed: true`.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostUsersUpdateJSONBodySeniority defines parameters for PostUsersUpdate.
+type PostUsersUpdateJSONBodySeniority string
+
+// PostAdminApiKeysCreateJSONRequestBody defines body for PostAdminApiKeysCreate for application/json ContentType.
+type PostAdminApiKeysCreateJSONRequestBody PostAdminApiKeysCreateJSONBody
+
+// PostAdminApiKeysRevokeJSONRequestBody defines body for PostAdminApiKeysRevoke for application/json ContentType.
+type PostAdminApiKeysRevokeJSONRequestBody PostAdminApiKeysRevokeJSONBody
+
+// PostAdminOrganizationsCreateJSONRequestBody defines body for PostAdminOrganizationsCreate for application/json ContentType.
+type PostAdminOrganizationsCreateJSONRequestBody PostAdminOrganizationsCreateJSONBody
+
+// PostPullRequestBulkCreateJSONRequestBody defines body for PostPullRequestBulkCreate for application/json ContentType.
+type PostPullRequestBulkCreateJSONRequestBody PostPullRequestBulkCreateJSONBody
+
 // PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
-type PostPullRequestCreateJSONRequestBody PostPullRequestCreateJSONBody
+type PostPullRequestCreateJSONRequestBody = PullRequestCreate
 
 // PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
 type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
 
+// PostPullRequestPreviewAssignmentJSONRequestBody defines body for PostPullRequestPreviewAssignment for application/json ContentType.
+type PostPullRequestPreviewAssignmentJSONRequestBody = PullRequestCreate
+
 // PostPullRequestReassignJSONRequestBody defines body for PostPullRequestReassign for application/json ContentType.
 type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
 
 // PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
 type PostTeamAddJSONRequestBody = Team
 
+// PostTeamAddMembersJSONRequestBody defines body for PostTeamAddMembers for application/json ContentType.
+type PostTeamAddMembersJSONRequestBody PostTeamAddMembersJSONBody
+
+// PostTeamRemoveMembersJSONRequestBody defines body for PostTeamRemoveMembers for application/json ContentType.
+type PostTeamRemoveMembersJSONRequestBody PostTeamRemoveMembersJSONBody
+
+// PostTeamRenameJSONRequestBody defines body for PostTeamRename for application/json ContentType.
+type PostTeamRenameJSONRequestBody PostTeamRenameJSONBody
+
+// PostTeamSetLeadRulesJSONRequestBody defines body for PostTeamSetLeadRules for application/json ContentType.
+type PostTeamSetLeadRulesJSONRequestBody PostTeamSetLeadRulesJSONBody
+
+// PostTeamSetMaxOpenReviewsJSONRequestBody defines body for PostTeamSetMaxOpenReviews for application/json ContentType.
+type PostTeamSetMaxOpenReviewsJSONRequestBody PostTeamSetMaxOpenReviewsJSONBody
+
+// PostTeamSetMemberActiveJSONRequestBody defines body for PostTeamSetMemberActive for application/json ContentType.
+type PostTeamSetMemberActiveJSONRequestBody PostTeamSetMemberActiveJSONBody
+
+// PostTeamSetParentJSONRequestBody defines body for PostTeamSetParent for application/json ContentType.
+type PostTeamSetParentJSONRequestBody PostTeamSetParentJSONBody
+
+// PostTeamSetSelectionModeJSONRequestBody defines body for PostTeamSetSelectionMode for application/json ContentType.
+type PostTeamSetSelectionModeJSONRequestBody PostTeamSetSelectionModeJSONBody
+
+// PostTeamUploadCodeownersJSONRequestBody defines body for PostTeamUploadCodeowners for application/json ContentType.
+type PostTeamUploadCodeownersJSONRequestBody = CodeownersUpload
+
+// PostTeamValidateCodeownersJSONRequestBody defines body for PostTeamValidateCodeowners for application/json ContentType.
+type PostTeamValidateCodeownersJSONRequestBody = CodeownersUpload
+
+// PostUsersAnonymizeJSONRequestBody defines body for PostUsersAnonymize for application/json ContentType.
+type PostUsersAnonymizeJSONRequestBody PostUsersAnonymizeJSONBody
+
 // PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
 type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
+
+// PostUsersSetSkillsJSONRequestBody defines body for PostUsersSetSkills for application/json ContentType.
+type PostUsersSetSkillsJSONRequestBody PostUsersSetSkillsJSONBody
+
+// PostUsersSetWorkingHoursJSONRequestBody defines body for PostUsersSetWorkingHours for application/json ContentType.
+type PostUsersSetWorkingHoursJSONRequestBody PostUsersSetWorkingHoursJSONBody
+
+// PostUsersUpdateJSONRequestBody defines body for PostUsersUpdate for application/json ContentType.
+type PostUsersUpdateJSONRequestBody PostUsersUpdateJSONBody
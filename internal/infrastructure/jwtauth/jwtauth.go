@@ -0,0 +1,189 @@
+// Package jwtauth проверяет bearer-токены HS256/RS256 по ключам из конфигурации.
+package jwtauth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"pr-service/internal/domain/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoKeys     = errors.New("jwt: neither hmac secret nor jwks file configured")
+	ErrUnknownKid = errors.New("jwt: unknown key id")
+	ErrNoOrg      = errors.New("jwt: org_id claim required")
+)
+
+type Config struct {
+	HMACSecret string
+	// JWKSPath — путь к JWKS-файлу (RSA и/или oct ключи)
+	JWKSPath string
+	Issuer   string
+	Audience string
+	// DefaultOrgID — организация токенов без org_id. Пустая — такие токены
+	// отклоняются: иначе admin-токен без org_id получил бы права
+	// администратора платформы.
+	DefaultOrgID string
+}
+
+// Claims — ожидаемые claims токена: sub — user_id или имя бота.
+type Claims struct {
+	Role     string `json:"role"`
+	TeamName string `json:"team_name"`
+	OrgID    string `json:"org_id"`
+	jwt.RegisteredClaims
+}
+
+type Verifier struct {
+	hmacKeys     map[string][]byte
+	rsaKeys      map[string]*rsa.PublicKey
+	parser       *jwt.Parser
+	defaultOrgID string
+}
+
+func New(cfg Config) (*Verifier, error) {
+	v := &Verifier{
+		hmacKeys:     make(map[string][]byte),
+		rsaKeys:      make(map[string]*rsa.PublicKey),
+		defaultOrgID: cfg.DefaultOrgID,
+	}
+	if cfg.HMACSecret != "" {
+		v.hmacKeys[""] = []byte(cfg.HMACSecret)
+	}
+	if cfg.JWKSPath != "" {
+		if err := v.loadJWKS(cfg.JWKSPath); err != nil {
+			return nil, err
+		}
+	}
+	if len(v.hmacKeys) == 0 && len(v.rsaKeys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+func (v *Verifier) Verify(token string) (auth.Principal, error) {
+	var claims Claims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.keyFunc); err != nil {
+		return auth.Principal{}, fmt.Errorf("%w: %w", auth.ErrInvalidToken, err)
+	}
+
+	role := auth.Role(claims.Role)
+	if !role.Valid() {
+		return auth.Principal{}, fmt.Errorf("%w: %w", auth.ErrInvalidToken, auth.ErrInvalidRole)
+	}
+	if claims.Subject == "" {
+		return auth.Principal{}, fmt.Errorf("%w: empty sub", auth.ErrInvalidToken)
+	}
+
+	orgID := claims.OrgID
+	if orgID == "" {
+		if v.defaultOrgID == "" {
+			return auth.Principal{}, fmt.Errorf("%w: %w", auth.ErrInvalidToken, ErrNoOrg)
+		}
+		orgID = v.defaultOrgID
+	}
+
+	return auth.Principal{
+		Subject:  claims.Subject,
+		Role:     role,
+		TeamName: claims.TeamName,
+		OrgID:    orgID,
+		Method:   auth.MethodJWT,
+	}, nil
+}
+
+func (v *Verifier) keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return lookup(v.hmacKeys, kid)
+	case *jwt.SigningMethodRSA:
+		return lookup(v.rsaKeys, kid)
+	}
+	return nil, fmt.Errorf("jwt: unexpected signing method %s", t.Method.Alg())
+}
+
+// lookup ищет ключ по kid; токен без kid допустим, если ключ единственный.
+func lookup[K any](keys map[string]K, kid string) (K, error) {
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	var zero K
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+	return zero, ErrUnknownKid
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		K   string `json:"k"`
+	} `json:"keys"`
+}
+
+func (v *Verifier) loadJWKS(path string) error {
+	const op = "jwtauth.loadJWKS"
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, k := range set.Keys {
+		switch k.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return fmt.Errorf("%s: key %q: n: %w", op, k.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil {
+				return fmt.Errorf("%s: key %q: e: %w", op, k.Kid, err)
+			}
+			v.rsaKeys[k.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil {
+				return fmt.Errorf("%s: key %q: k: %w", op, k.Kid, err)
+			}
+			v.hmacKeys[k.Kid] = secret
+		default:
+			return fmt.Errorf("%s: key %q: unsupported kty %q", op, k.Kid, k.Kty)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package jwtauth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"pr-service/internal/domain/auth"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const hmacSecret = "test-secret"
+
+// testKeys — RSA-ключ с kid "rsa-1" и oct-ключ с kid "oct-1" в JWKS-файле.
+type testKeys struct {
+	rsa  *rsa.PrivateKey
+	oct  []byte
+	jwks string
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	oct := []byte("jwks-oct-secret")
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	set := map[string]any{"keys": []map[string]string{
+		{
+			"kty": "RSA", "kid": "rsa-1", "alg": "RS256",
+			"n": b64(key.N.Bytes()),
+			"e": b64(big.NewInt(int64(key.E)).Bytes()),
+		},
+		{"kty": "oct", "kid": "oct-1", "alg": "HS256", "k": b64(oct)},
+	}}
+	raw, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("marshal jwks: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+	return testKeys{rsa: key, oct: oct, jwks: path}
+}
+
+func claims(mutate func(*Claims)) Claims {
+	c := Claims{
+		Role:  string(auth.RoleMember),
+		OrgID: "acme",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "u1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	if mutate != nil {
+		mutate(&c)
+	}
+	return c
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, c Claims, key any) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestVerifierVerify(t *testing.T) {
+	keys := newTestKeys(t)
+	pubDER, err := x509.MarshalPKIXPublicKey(&keys.rsa.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+
+	v, err := New(Config{HMACSecret: hmacSecret, JWKSPath: keys.jwks})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	withDefault, err := New(Config{HMACSecret: hmacSecret, DefaultOrgID: auth.DefaultOrgID})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		token    string
+		want     auth.Principal
+		wantErr  error
+	}{
+		{
+			name:     "HS256 с секретом из конфигурации",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodHS256, "", claims(nil), []byte(hmacSecret)),
+			want:     auth.Principal{Subject: "u1", Role: auth.RoleMember, OrgID: "acme", Method: auth.MethodJWT},
+		},
+		{
+			name:     "HS256 с oct-ключом из JWKS",
+			verifier: v,
+			token: sign(t, jwt.SigningMethodHS256, "oct-1", claims(func(c *Claims) {
+				c.Role, c.TeamName = string(auth.RoleTeamLead), "backend"
+			}), keys.oct),
+			want: auth.Principal{Subject: "u1", Role: auth.RoleTeamLead, TeamName: "backend", OrgID: "acme", Method: auth.MethodJWT},
+		},
+		{
+			name:     "RS256 по kid",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodRS256, "rsa-1", claims(nil), keys.rsa),
+			want:     auth.Principal{Subject: "u1", Role: auth.RoleMember, OrgID: "acme", Method: auth.MethodJWT},
+		},
+		{
+			name:     "HS256, подписанный открытым RSA-ключом",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodHS256, "rsa-1", claims(nil), pubDER),
+			wantErr:  ErrUnknownKid,
+		},
+		{
+			name:     "RS256 с kid oct-ключа",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodRS256, "oct-1", claims(nil), keys.rsa),
+			wantErr:  ErrUnknownKid,
+		},
+		{
+			name:     "неизвестный kid",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodRS256, "rsa-2", claims(nil), keys.rsa),
+			wantErr:  ErrUnknownKid,
+		},
+		{
+			name:     "RS256 без kid при единственном RSA-ключе",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodRS256, "", claims(nil), keys.rsa),
+			want:     auth.Principal{Subject: "u1", Role: auth.RoleMember, OrgID: "acme", Method: auth.MethodJWT},
+		},
+		{
+			name:     "чужой секрет",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodHS256, "", claims(nil), []byte("other")),
+			wantErr:  jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:     "неразрешённый алгоритм",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodHS512, "", claims(nil), []byte(hmacSecret)),
+			wantErr:  jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:     "истёкший токен",
+			verifier: v,
+			token: sign(t, jwt.SigningMethodHS256, "", claims(func(c *Claims) {
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+			}), []byte(hmacSecret)),
+			wantErr: jwt.ErrTokenExpired,
+		},
+		{
+			name:     "без exp",
+			verifier: v,
+			token: sign(t, jwt.SigningMethodHS256, "", claims(func(c *Claims) {
+				c.ExpiresAt = nil
+			}), []byte(hmacSecret)),
+			wantErr: jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:     "неизвестная роль",
+			verifier: v,
+			token: sign(t, jwt.SigningMethodHS256, "", claims(func(c *Claims) {
+				c.Role = "root"
+			}), []byte(hmacSecret)),
+			wantErr: auth.ErrInvalidRole,
+		},
+		{
+			name:     "без sub",
+			verifier: v,
+			token: sign(t, jwt.SigningMethodHS256, "", claims(func(c *Claims) {
+				c.Subject = ""
+			}), []byte(hmacSecret)),
+			wantErr: auth.ErrInvalidToken,
+		},
+		{
+			name:     "без org_id",
+			verifier: v,
+			token: sign(t, jwt.SigningMethodHS256, "", claims(func(c *Claims) {
+				c.Role, c.OrgID = string(auth.RoleAdmin), ""
+			}), []byte(hmacSecret)),
+			wantErr: ErrNoOrg,
+		},
+		{
+			name:     "без org_id с организацией по умолчанию",
+			verifier: withDefault,
+			token: sign(t, jwt.SigningMethodHS256, "", claims(func(c *Claims) {
+				c.OrgID = ""
+			}), []byte(hmacSecret)),
+			want: auth.Principal{Subject: "u1", Role: auth.RoleMember, OrgID: auth.DefaultOrgID, Method: auth.MethodJWT},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verifier.Verify(tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, auth.ErrInvalidToken) {
+					t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewJWKSErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr error
+	}{
+		{"нет ключей", Config{}, ErrNoKeys},
+		{"пустой JWKS", Config{JWKSPath: write("empty.json", `{"keys": []}`)}, ErrNoKeys},
+		{"нет файла", Config{JWKSPath: filepath.Join(dir, "missing.json")}, os.ErrNotExist},
+		{"не JSON", Config{JWKSPath: write("bad.json", `keys`)}, nil},
+		{"неподдерживаемый kty", Config{JWKSPath: write("ec.json", `{"keys": [{"kty": "EC", "kid": "ec-1"}]}`)}, nil},
+		{"некорректный n", Config{JWKSPath: write("n.json", `{"keys": [{"kty": "RSA", "kid": "r", "n": "!!", "e": "AQAB"}]}`)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			if err == nil {
+				t.Fatal("New() error = nil")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package metrics содержит коллекторы Prometheus для HTTP, базы и доменных событий.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "pr_service"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	prCreated    prometheus.Counter
+	prMerged     prometheus.Counter
+	prReassigned prometheus.Counter
+	noCandidate  *prometheus.CounterVec
+	escalated    *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		prCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_created_total",
+			Help:      "Number of created pull requests.",
+		}),
+		prMerged: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_merged_total",
+			Help:      "Number of pull requests moved to MERGED.",
+		}),
+		prReassigned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_reassigned_total",
+			Help:      "Number of reviewer reassignments.",
+		}),
+		noCandidate: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "no_candidate_total",
+			Help:      "Number of times no reviewer candidate was found, by organization, team and reason (no_candidate or all_at_capacity).",
+		}, []string{"org", "team", "reason"}),
+		escalated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_escalations_total",
+			Help:      "Number of times reviewers were taken from a parent team, by organization and original team.",
+		}, []string{"org", "team"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.prCreated,
+		m.prMerged,
+		m.prReassigned,
+		m.noCandidate,
+		m.escalated,
+	)
+
+	return m
+}
+
+// RegisterDB экспортирует статистику пула sql.DB (открытые, простаивающие и занятые соединения, ожидания).
+func (m *Metrics) RegisterDB(db *sql.DB, dbName string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Registry открыт, чтобы другие компоненты регистрировали в нём свои коллекторы.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler обслуживает эндпоинт /metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) ObserveHTTP(route, method string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(route, method, code).Inc()
+	m.httpDuration.WithLabelValues(route, method, code).Observe(duration.Seconds())
+}
+
+func (m *Metrics) PullRequestCreated()    { m.prCreated.Inc() }
+func (m *Metrics) PullRequestMerged()     { m.prMerged.Inc() }
+func (m *Metrics) PullRequestReassigned() { m.prReassigned.Inc() }
+
+func (m *Metrics) NoCandidate(org, team, reason string) {
+	m.noCandidate.WithLabelValues(org, team, reason).Inc()
+}
+
+func (m *Metrics) Escalated(org, team string) {
+	m.escalated.WithLabelValues(org, team).Inc()
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+
+	"gorm.io/gorm"
+)
+
+func (p *PostgresStorage) APIKeyCreate(ctx context.Context, key auth.APIKey, hash string) error {
+	const op = "storage.postgres.APIKeyCreate"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	model := apiKeyModel(key, hash)
+	if err := p.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// APIKeyReplace отзывает действующие ключи организации key.OrgID с тем же
+// Subject и создаёт key в одной транзакции. Возвращает число отозванных ключей.
+func (p *PostgresStorage) APIKeyReplace(ctx context.Context, key auth.APIKey, hash string) (int64, error) {
+	const op = "storage.postgres.APIKeyReplace"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var revoked int64
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		res := tx.Model(&pgdto.APIKeyModel{}).
+			Where("org_id = ? AND subject = ? AND revoked_at IS NULL", key.OrgID, key.Subject).
+			Update("revoked_at", gorm.Expr("NOW()"))
+		if res.Error != nil {
+			return res.Error
+		}
+		revoked = res.RowsAffected
+
+		model := apiKeyModel(key, hash)
+		return tx.Create(&model).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return revoked, nil
+}
+
+func apiKeyModel(key auth.APIKey, hash string) pgdto.APIKeyModel {
+	model := pgdto.APIKeyModel{
+		KeyID:     key.KeyID,
+		OrgID:     key.OrgID,
+		Name:      key.Name,
+		KeyHash:   hash,
+		Role:      string(key.Role),
+		Subject:   key.Subject,
+		CreatedAt: key.CreatedAt,
+	}
+	if key.TeamName != "" {
+		model.TeamName = &key.TeamName
+	}
+	return model
+}
+
+func (p *PostgresStorage) APIKeyByHash(ctx context.Context, hash string) (auth.APIKey, error) {
+	const op = "storage.postgres.APIKeyByHash"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var model pgdto.APIKeyModel
+	err := p.db.WithContext(ctx).
+		Where("key_hash = ?", hash).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return auth.APIKey{}, auth.ErrKeyNotFound
+		}
+		return auth.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return model.ToDomain(), nil
+}
+
+func (p *PostgresStorage) APIKeyList(ctx context.Context) ([]auth.APIKey, error) {
+	const op = "storage.postgres.APIKeyList"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var models []pgdto.APIKeyModel
+	if err := p.db.WithContext(ctx).
+		Where("org_id = ?", auth.OrgID(ctx)).
+		Order("created_at").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	keys := make([]auth.APIKey, 0, len(models))
+	for _, m := range models {
+		keys = append(keys, m.ToDomain())
+	}
+	return keys, nil
+}
+
+func (p *PostgresStorage) APIKeyRevoke(ctx context.Context, id string) error {
+	const op = "storage.postgres.APIKeyRevoke"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	res := p.db.WithContext(ctx).
+		Model(&pgdto.APIKeyModel{}).
+		Where("org_id = ? AND key_id = ? AND revoked_at IS NULL", auth.OrgID(ctx), id).
+		Update("revoked_at", gorm.Expr("NOW()"))
+	if res.Error != nil {
+		return fmt.Errorf("%s: %w", op, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return auth.ErrKeyNotFound
+	}
+	return nil
+}
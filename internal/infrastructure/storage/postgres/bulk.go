@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// bulkInsertBatch — строк в одном INSERT при пакетной записи
+const bulkInsertBatch = 500
+
+func (p *PostgresStorage) PullRequestsExisting(ctx context.Context, ids []string) ([]string, error) {
+	const op = "storage.postgres.PullRequestsExisting"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	existing := []string{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	if err := p.db.WithContext(ctx).Table("pull_requests").
+		Where("org_id = ? AND pull_request_id IN ?", auth.OrgID(ctx), ids).
+		Pluck("pull_request_id", &existing).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return existing, nil
+}
+
+// PullRequestBulkCreate записывает пакет PR с ревьюверами, историей пар и
+// обоснованием подбора многострочными INSERT в одной транзакции. Назначения
+// датируются created_at PR, чтобы перенесённая история не выглядела свежей.
+// PR, которые уже есть, пропускаются и возвращаются.
+func (p *PostgresStorage) PullRequestBulkCreate(ctx context.Context, items []pr.BulkCreateItem) ([]string, error) {
+	const op = "storage.postgres.PullRequestBulkCreate"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.PullRequest.PullRequestId)
+	}
+
+	var existing []string
+	insert := func(tx *gorm.DB) error {
+		existing = existing[:0]
+		if err := tx.Table("pull_requests").
+			Where("org_id = ? AND pull_request_id IN ?", org, ids).
+			Pluck("pull_request_id", &existing).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var prRows, reviewerRows, pairingRows, historyRows []map[string]interface{}
+		for _, item := range items {
+			prEntity := item.PullRequest
+			if slices.Contains(existing, prEntity.PullRequestId) {
+				continue
+			}
+			createdAt := now
+			if prEntity.CreatedAt != nil {
+				createdAt = *prEntity.CreatedAt
+			}
+
+			prRows = append(prRows, map[string]interface{}{
+				"org_id":            org,
+				"pull_request_id":   prEntity.PullRequestId,
+				"pull_request_name": prEntity.PullRequestName,
+				"author_id":         prEntity.AuthorId,
+				"status":            prEntity.Status,
+				"created_at":        createdAt,
+				"merged_at":         prEntity.MergedAt,
+				"version":           prEntity.Version,
+				"team_name":         nullIfEmpty(prEntity.TeamName),
+				"labels":            pq.StringArray(append([]string{}, prEntity.Labels...)),
+			})
+			for _, userID := range prEntity.AssignedReviewers {
+				reviewerRows = append(reviewerRows, map[string]interface{}{
+					"org_id":          org,
+					"pull_request_id": prEntity.PullRequestId,
+					"user_id":         userID,
+					"assigned_at":     createdAt,
+				})
+				pairingRows = append(pairingRows, map[string]interface{}{
+					"org_id":          org,
+					"pull_request_id": prEntity.PullRequestId,
+					"author_id":       prEntity.AuthorId,
+					"reviewer_id":     userID,
+					"assigned_at":     createdAt,
+				})
+			}
+
+			details, err := json.Marshal(pgdto.AssignmentDetailsFromDomain(item.Explanation))
+			if err != nil {
+				return err
+			}
+			historyRows = append(historyRows, map[string]interface{}{
+				"org_id":          org,
+				"pull_request_id": prEntity.PullRequestId,
+				"event":           string(pr.HistoryCreated),
+				"seed":            item.Explanation.Seed,
+				"assigned_at":     item.Explanation.AssignedAt,
+				"details":         string(details),
+				"created_at":      createdAt,
+			})
+		}
+
+		for _, batch := range []struct {
+			table string
+			rows  []map[string]interface{}
+		}{
+			{"pull_requests", prRows},
+			{"pull_request_reviewers", reviewerRows},
+			{"review_pairings", pairingRows},
+			{"pull_request_history", historyRows},
+		} {
+			if len(batch.rows) == 0 {
+				continue
+			}
+			if err := tx.Table(batch.table).CreateInBatches(batch.rows, bulkInsertBatch).Error; err != nil {
+				return fmt.Errorf("insert %s: %w", batch.table, err)
+			}
+		}
+		return nil
+	}
+
+	err := p.transaction(ctx, nil, insert)
+	if isUniqueViolation(err) {
+		// параллельный запрос создал PR из пакета после проверки; при повторе
+		// он попадёт в existing
+		err = p.transaction(ctx, nil, insert)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return existing, nil
+}
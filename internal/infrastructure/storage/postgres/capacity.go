@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// openReviewsSQL — число открытых PR, в которых ревьювер — пользователь u.
+const openReviewsSQL = `(
+	SELECT COUNT(*)
+	FROM pull_request_reviewers r2
+	JOIN pull_requests p2 ON p2.org_id = r2.org_id AND p2.pull_request_id = r2.pull_request_id
+	WHERE r2.org_id = u.org_id AND r2.user_id = u.user_id AND p2.status = 'OPEN'
+)`
+
+// atCapacitySQL — у пользователя u исчерпан лимит открытых ревью: личный,
+// а если его нет — лимит команды t, через которую он назначается.
+// Без обоих лимитов условие ложно.
+const atCapacitySQL = `(COALESCE(u.max_open_reviews, t.max_open_reviews) <= ` + openReviewsSQL + `) IS TRUE`
+
+// atCapacityCond — atCapacitySQL, если подбор учитывает лимит открытых ревью.
+func atCapacityCond(ctx context.Context) string {
+	if pr.ReviewLimitsIgnored(ctx) {
+		return "FALSE"
+	}
+	return atCapacitySQL
+}
+
+// OpenReviewsLeft считает оставшиеся места по лимиту: личному, а если его
+// нет — лимиту команды team. Без обоих лимитов пользователь не возвращается.
+func (p *PostgresStorage) OpenReviewsLeft(ctx context.Context, team string, ids []string) (map[string]int, error) {
+	const op = "storage.postgres.OpenReviewsLeft"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var rows []struct {
+		UserID      string
+		ReviewsLeft int
+	}
+	if err := p.db.WithContext(ctx).Raw(`
+		SELECT u.user_id,
+		       COALESCE(u.max_open_reviews, t.max_open_reviews) - `+openReviewsSQL+` AS reviews_left
+		FROM users u
+		LEFT JOIN teams t ON t.org_id = u.org_id AND t.team_name = ?
+		WHERE u.org_id = ?
+		  AND u.user_id = ANY(?)
+		  AND COALESCE(u.max_open_reviews, t.max_open_reviews) IS NOT NULL
+	`, team, auth.OrgID(ctx), pq.StringArray(ids)).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	left := make(map[string]int, len(rows))
+	for _, row := range rows {
+		left[row.UserID] = row.ReviewsLeft
+	}
+	return left, nil
+}
+
+func (p *PostgresStorage) TeamSetMaxOpenReviews(ctx context.Context, r pr.TeamSetMaxOpenReviews) (pr.Team, error) {
+	const op = "storage.postgres.TeamSetMaxOpenReviews"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var team pr.Team
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		upd := tx.Model(&pgdto.TeamModel{}).
+			Where("org_id = ? AND team_name = ?", org, r.TeamName).
+			Update("max_open_reviews", nullIfZero(r.MaxOpenReviews))
+		if upd.Error != nil {
+			return upd.Error
+		}
+		if upd.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		var err error
+		team, err = loadTeam(tx, org, r.TeamName)
+		return err
+	})
+	if err != nil {
+		return pr.Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return team, nil
+}
+
+// nullIfZero — 0 в лимитах означает «не задан».
+func nullIfZero(n int) *int {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
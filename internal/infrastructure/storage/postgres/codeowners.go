@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+	"slices"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+func (p *PostgresStorage) TeamCodeowners(ctx context.Context, teamName string) ([]pr.CodeownersRule, error) {
+	const op = "storage.postgres.TeamCodeowners"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var rows []pgdto.TeamCodeownerModel
+	if err := p.db.WithContext(ctx).
+		Where("org_id = ? AND team_name = ?", auth.OrgID(ctx), teamName).
+		Order("position").
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rules := make([]pr.CodeownersRule, 0, len(rows))
+	for _, row := range rows {
+		rules = append(rules, row.ToDomain())
+	}
+	return rules, nil
+}
+
+func (p *PostgresStorage) TeamSetCodeowners(ctx context.Context, teamName string, rules []pr.CodeownersRule) error {
+	const op = "storage.postgres.TeamSetCodeowners"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		if err := lockTeam(tx, org, teamName); err != nil {
+			return err
+		}
+		if err := tx.Where("org_id = ? AND team_name = ?", org, teamName).
+			Delete(&pgdto.TeamCodeownerModel{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+
+		rows := make([]pgdto.TeamCodeownerModel, 0, len(rules))
+		for i, rule := range rules {
+			rows = append(rows, pgdto.TeamCodeownerModel{
+				OrgID:      org,
+				TeamName:   teamName,
+				Position:   i,
+				Line:       rule.Line,
+				Pattern:    rule.Pattern,
+				OwnerUsers: pq.StringArray(append([]string{}, rule.Users...)),
+				OwnerTeams: pq.StringArray(append([]string{}, rule.Teams...)),
+			})
+		}
+		return tx.CreateInBatches(rows, 100).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (p *PostgresStorage) UnknownOwners(ctx context.Context, users, teams []string) ([]string, []string, error) {
+	const op = "storage.postgres.UnknownOwners"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+	org := auth.OrgID(ctx)
+
+	// анонимизированный пользователь для владельцев всё равно что удалённый
+	var foundUsers, foundTeams []string
+	if len(users) > 0 {
+		if err := db.Model(&pgdto.UserModel{}).
+			Where("org_id = ? AND user_id IN ? AND anonymized_at IS NULL", org, users).
+			Pluck("user_id", &foundUsers).Error; err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if len(teams) > 0 {
+		if err := db.Model(&pgdto.TeamModel{}).
+			Where("org_id = ? AND team_name IN ?", org, teams).
+			Pluck("team_name", &foundTeams).Error; err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	missing := func(all, found []string) []string {
+		var res []string
+		for _, v := range all {
+			if !slices.Contains(found, v) && !slices.Contains(res, v) {
+				res = append(res, v)
+			}
+		}
+		return res
+	}
+	return missing(users, foundUsers), missing(teams, foundTeams), nil
+}
+
+// AvailableOwners — пользователи-владельцы, активные в целом, и участники
+// команд-владельцев, активные и в целом, и в команде. Автор PR исключается.
+// Владельцы с исчерпанным лимитом открытых ревью возвращаются отдельно:
+// участнику команды-владельца действует лимит этой команды, остальным —
+// лимит команды PR teamName.
+func (p *PostgresStorage) AvailableOwners(ctx context.Context, teamName string, users, teams []string, authorID string) (available, atCapacity []string, err error) {
+	const op = "storage.postgres.AvailableOwners"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var rows []struct {
+		UserID string
+		Reason string
+	}
+	if err := p.db.WithContext(ctx).Raw(`
+		SELECT u.user_id,
+		       CASE
+		         WHEN (u.user_id = ANY(?) AND NOT `+atCapacityCond(ctx)+`)
+		           OR EXISTS (
+		              SELECT 1 FROM team_memberships m
+		              JOIN teams t ON t.org_id = m.org_id AND t.team_name = m.team_name
+		              WHERE m.org_id = u.org_id AND m.user_id = u.user_id
+		                AND m.is_active = true
+		                AND m.team_name = ANY(?)
+		                AND NOT `+atCapacityCond(ctx)+`
+		           ) THEN ''
+		         ELSE 'at_capacity'
+		       END AS reason
+		FROM users u
+		LEFT JOIN teams t ON t.org_id = u.org_id AND t.team_name = ?
+		WHERE u.org_id = ?
+		  AND u.user_id != ?
+		  AND u.is_active = true
+		  AND (
+		    u.user_id = ANY(?)
+		    OR EXISTS (
+		        SELECT 1 FROM team_memberships m
+		        WHERE m.org_id = u.org_id AND m.user_id = u.user_id
+		          AND m.is_active = true
+		          AND m.team_name = ANY(?)
+		    )
+		  )
+		ORDER BY u.user_id
+	`, pq.StringArray(users), pq.StringArray(teams), teamName,
+		auth.OrgID(ctx), authorID, pq.StringArray(users), pq.StringArray(teams)).
+		Scan(&rows).Error; err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, row := range rows {
+		if row.Reason == string(pr.ExclusionAtCapacity) {
+			atCapacity = append(atCapacity, row.UserID)
+			continue
+		}
+		available = append(available, row.UserID)
+	}
+	return available, atCapacity, nil
+}
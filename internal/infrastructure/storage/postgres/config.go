@@ -4,4 +4,7 @@ type Config struct {
 	DSN            string
 	Seed           bool
 	MigrationsPath string
+	// SkipMigrations — не применять миграции при подключении; prctl
+	// управляет ими отдельной командой
+	SkipMigrations bool
 }
@@ -2,52 +2,212 @@
 package pgdto
 
 import (
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/idempotency"
 	"pr-service/internal/domain/pr"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type PullRequest struct {
+    OrgID           string    `gorm:"column:org_id;primaryKey;type:text"`
     PullRequestID   string    `gorm:"column:pull_request_id;primaryKey;type:text"`
     PullRequestName string    `gorm:"column:pull_request_name;type:text;not null"`
     AuthorID        string    `gorm:"column:author_id;type:text;not null"`
     Status          string    `gorm:"column:status;type:text;not null"`
     CreatedAt       time.Time `gorm:"column:created_at"`
     MergedAt        *time.Time `gorm:"column:merged_at"`
+    Version         int64     `gorm:"column:version"`
+    TeamName        *string   `gorm:"column:team_name"`
+    Labels          pq.StringArray `gorm:"column:labels;type:text[]"`
 
-    AssignedReviewers []User `gorm:"many2many:pull_request_reviewers;foreignKey:PullRequestID;joinForeignKey:pull_request_id;References:UserID;joinReferences:user_id"`
+    // AssignedReviewers загружается отдельным запросом с учётом организации
+    AssignedReviewers []string `gorm:"-"`
 }
 
 type User struct {
+    OrgID  string `gorm:"column:org_id;primaryKey;type:text"`
     UserID string `gorm:"column:user_id;primaryKey;type:text"`
 	TeamName string `gorm:"column:team_name;type:text;foreignKey:team_name;references:TeamName"`
 }
 
 type PullRequestReviewer struct {
+	OrgID         string `gorm:"primaryKey;column:org_id;type:text"`
 	PullRequestID string `gorm:"primaryKey;column:pull_request_id;type:text"`
 	UserID        string `gorm:"primaryKey;column:user_id;type:text"`
 }
 
 type TeamModel struct {
-	TeamName string `gorm:"primaryKey;column:team_name"`
+	OrgID      string  `gorm:"primaryKey;column:org_id"`
+	TeamName   string  `gorm:"primaryKey;column:team_name"`
+	ParentTeam *string `gorm:"column:parent_team"`
+	// пустой режим при создании — значение по умолчанию из БД
+	SelectionMode      string `gorm:"column:selection_mode;default:random"`
+	MaxOpenReviews     *int   `gorm:"column:max_open_reviews"`
+	PreferWorkingHours bool   `gorm:"column:prefer_working_hours"`
 }
 
 type UserModel struct {
-	UserID   string  `gorm:"primaryKey;column:user_id"`
-	Username string  `gorm:"column:username"`
-	IsActive bool    `gorm:"column:is_active"`
-	TeamName *string `gorm:"column:team_name"` 
+	OrgID          string     `gorm:"primaryKey;column:org_id"`
+	UserID         string     `gorm:"primaryKey;column:user_id"`
+	Username       string     `gorm:"column:username"`
+	IsActive       bool       `gorm:"column:is_active"`
+	TeamName       *string    `gorm:"column:team_name"` 
+	AnonymizedAt   *time.Time `gorm:"column:anonymized_at"`
+	Seniority      *string    `gorm:"column:seniority"`
+	MaxOpenReviews *int       `gorm:"column:max_open_reviews"`
+
+	WorkingHoursColumns `gorm:"embedded"`
+}
+
+func (u *UserModel) ToDomain() pr.User {
+	user := pr.User{
+		UserId:       u.UserID,
+		Username:     u.Username,
+		IsActive:     u.IsActive,
+		AnonymizedAt: u.AnonymizedAt,
+	}
+	if u.TeamName != nil {
+		user.TeamName = *u.TeamName
+	}
+	if u.Seniority != nil {
+		user.Seniority = pr.Seniority(*u.Seniority)
+	}
+	if u.MaxOpenReviews != nil {
+		user.MaxOpenReviews = *u.MaxOpenReviews
+	}
+	user.WorkingHours = u.WorkingHoursColumns.ToDomain()
+	return user
+}
+
+// WorkingHoursColumns — рабочее время в users; дни хранятся по ISO
+// (1 — понедельник, 7 — воскресенье).
+type WorkingHoursColumns struct {
+	Timezone  *string       `gorm:"column:timezone"`
+	WorkStart *int          `gorm:"column:work_start"`
+	WorkEnd   *int          `gorm:"column:work_end"`
+	WorkDays  pq.Int64Array `gorm:"column:work_days;type:smallint[]"`
+}
+
+func (c WorkingHoursColumns) ToDomain() *pr.WorkingHours {
+	if c.Timezone == nil || c.WorkStart == nil || c.WorkEnd == nil {
+		return nil
+	}
+	w := &pr.WorkingHours{Timezone: *c.Timezone, Start: *c.WorkStart, End: *c.WorkEnd}
+	for _, d := range c.WorkDays {
+		w.Days = append(w.Days, time.Weekday(d%7))
+	}
+	return w
+}
+
+// WorkingHoursFromDomain — значения колонок; nil снимает рабочее время.
+func WorkingHoursFromDomain(w *pr.WorkingHours) WorkingHoursColumns {
+	if w == nil {
+		return WorkingHoursColumns{}
+	}
+	c := WorkingHoursColumns{Timezone: &w.Timezone, WorkStart: &w.Start, WorkEnd: &w.End}
+	for _, d := range w.Days {
+		if d == time.Sunday {
+			c.WorkDays = append(c.WorkDays, 7)
+			continue
+		}
+		c.WorkDays = append(c.WorkDays, int64(d))
+	}
+	return c
+}
+
+type TeamMembershipModel struct {
+	OrgID     string    `gorm:"primaryKey;column:org_id"`
+	TeamName  string    `gorm:"primaryKey;column:team_name"`
+	UserID    string    `gorm:"primaryKey;column:user_id"`
+	Role      string    `gorm:"column:role"`
+	IsActive  bool      `gorm:"column:is_active"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (TeamMembershipModel) TableName() string { return "team_memberships" }
+
+type TeamLeadRuleModel struct {
+	OrgID    string `gorm:"primaryKey;column:org_id"`
+	TeamName string `gorm:"primaryKey;column:team_name"`
+	UserID   string `gorm:"primaryKey;column:user_id"`
+	Kind     string `gorm:"primaryKey;column:kind"`
+	Value    string `gorm:"primaryKey;column:value"`
+}
+
+func (TeamLeadRuleModel) TableName() string { return "team_lead_rules" }
+
+type ReviewPairingModel struct {
+	OrgID         string    `gorm:"primaryKey;column:org_id"`
+	PullRequestID string    `gorm:"primaryKey;column:pull_request_id"`
+	AuthorID      string    `gorm:"column:author_id"`
+	ReviewerID    string    `gorm:"primaryKey;column:reviewer_id"`
+	AssignedAt    time.Time `gorm:"column:assigned_at"`
+}
+
+func (ReviewPairingModel) TableName() string { return "review_pairings" }
+
+type UserSkillModel struct {
+	OrgID  string `gorm:"primaryKey;column:org_id"`
+	UserID string `gorm:"primaryKey;column:user_id"`
+	Tag    string `gorm:"primaryKey;column:tag"`
+	Level  string `gorm:"column:level"`
+}
+
+func (UserSkillModel) TableName() string { return "user_skills" }
+
+func (m *UserSkillModel) ToDomain() pr.Skill {
+	return pr.Skill{Tag: m.Tag, Level: pr.SkillLevel(m.Level)}
+}
+
+type TeamCodeownerModel struct {
+	OrgID      string         `gorm:"primaryKey;column:org_id"`
+	TeamName   string         `gorm:"primaryKey;column:team_name"`
+	Position   int            `gorm:"primaryKey;column:position"`
+	Line       int            `gorm:"column:line"`
+	Pattern    string         `gorm:"column:pattern"`
+	OwnerUsers pq.StringArray `gorm:"column:owner_users;type:text[]"`
+	OwnerTeams pq.StringArray `gorm:"column:owner_teams;type:text[]"`
+}
+
+func (TeamCodeownerModel) TableName() string { return "team_codeowners" }
+
+func (m *TeamCodeownerModel) ToDomain() pr.CodeownersRule {
+	return pr.CodeownersRule{
+		Line:    m.Line,
+		Pattern: m.Pattern,
+		Users:   append([]string{}, m.OwnerUsers...),
+		Teams:   append([]string{}, m.OwnerTeams...),
+	}
+}
+
+func (m *TeamLeadRuleModel) ToDomain() pr.LeadRule {
+	return pr.LeadRule{Kind: pr.LeadRuleKind(m.Kind), Value: m.Value}
 }
 
 func (TeamModel) TableName() string { return "teams" }
 func (UserModel) TableName() string { return "users" }
+func (User) TableName() string { return "users" }
+func (PullRequestReviewer) TableName() string { return "pull_request_reviewers" }
+
+type OrganizationModel struct {
+	OrgID     string    `gorm:"primaryKey;column:org_id"`
+	Name      string    `gorm:"column:name"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (OrganizationModel) TableName() string { return "organizations" }
+
+func (o *OrganizationModel) ToDomain() auth.Organization {
+	return auth.Organization{OrgID: o.OrgID, Name: o.Name, CreatedAt: o.CreatedAt}
+}
 
 func (p *PullRequest) ToDomain() pr.PullRequest {
 	reviewers := make([]string, 0, len(p.AssignedReviewers))
-	for _, u := range p.AssignedReviewers {
-		reviewers = append(reviewers, u.UserID)
-	}
+	reviewers = append(reviewers, p.AssignedReviewers...)
 
-	return pr.PullRequest{
+	pullRequest := pr.PullRequest{
 		PullRequestId:     p.PullRequestID,
 		PullRequestName:   p.PullRequestName,
 		AuthorId:          p.AuthorID,
@@ -55,5 +215,161 @@ func (p *PullRequest) ToDomain() pr.PullRequest {
 		CreatedAt:         &p.CreatedAt,
 		MergedAt:          p.MergedAt,
 		AssignedReviewers: reviewers,
+		Version:           p.Version,
+		Labels:            append([]string{}, p.Labels...),
+	}
+	if p.TeamName != nil {
+		pullRequest.TeamName = *p.TeamName
+	}
+	return pullRequest
+}
+
+type APIKeyModel struct {
+	KeyID     string     `gorm:"primaryKey;column:key_id"`
+	OrgID     string     `gorm:"column:org_id"`
+	Name      string     `gorm:"column:name"`
+	KeyHash   string     `gorm:"column:key_hash"`
+	Role      string     `gorm:"column:role"`
+	Subject   string     `gorm:"column:subject"`
+	TeamName  *string    `gorm:"column:team_name"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+}
+
+func (APIKeyModel) TableName() string { return "api_keys" }
+
+func (k *APIKeyModel) ToDomain() auth.APIKey {
+	key := auth.APIKey{
+		KeyID:     k.KeyID,
+		Name:      k.Name,
+		Role:      auth.Role(k.Role),
+		Subject:   k.Subject,
+		OrgID:     k.OrgID,
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
 	}
-}
\ No newline at end of file
+	if k.TeamName != nil {
+		key.TeamName = *k.TeamName
+	}
+	return key
+}
+
+type IdempotencyKeyModel struct {
+	OrgID        string    `gorm:"primaryKey;column:org_id"`
+	Subject      string    `gorm:"primaryKey;column:subject"`
+	IdemKey      string    `gorm:"primaryKey;column:idem_key"`
+	RequestHash  string    `gorm:"column:request_hash"`
+	StatusCode   *int      `gorm:"column:status_code"`
+	ContentType  *string   `gorm:"column:content_type"`
+	ETag         *string   `gorm:"column:etag"`
+	ResponseBody []byte    `gorm:"column:response_body"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+	ExpiresAt    time.Time `gorm:"column:expires_at"`
+	LockedUntil  time.Time `gorm:"column:locked_until"`
+}
+
+func (IdempotencyKeyModel) TableName() string { return "idempotency_keys" }
+
+func (m *IdempotencyKeyModel) ToDomain() idempotency.Record {
+	rec := idempotency.Record{
+		OrgID:       m.OrgID,
+		Subject:     m.Subject,
+		Key:         m.IdemKey,
+		RequestHash: m.RequestHash,
+		Body:        m.ResponseBody,
+		CreatedAt:   m.CreatedAt,
+		ExpiresAt:   m.ExpiresAt,
+		LockedUntil: m.LockedUntil,
+	}
+	if m.StatusCode != nil {
+		rec.StatusCode = *m.StatusCode
+		rec.Completed = true
+	}
+	if m.ContentType != nil {
+		rec.ContentType = *m.ContentType
+	}
+	if m.ETag != nil {
+		rec.ETag = *m.ETag
+	}
+	return rec
+}
+
+type PullRequestHistoryModel struct {
+	ID            int64      `gorm:"primaryKey;column:id"`
+	OrgID         string     `gorm:"column:org_id"`
+	PullRequestID string     `gorm:"column:pull_request_id"`
+	Event         string     `gorm:"column:event"`
+	Seed          *int64     `gorm:"column:seed"`
+	AssignedAt    *time.Time `gorm:"column:assigned_at"`
+	Details       []byte     `gorm:"column:details"`
+	CreatedAt     time.Time  `gorm:"column:created_at"`
+}
+
+func (PullRequestHistoryModel) TableName() string { return "pull_request_history" }
+
+// AssignmentDetails — обоснование подбора ревьюверов в details события created.
+type AssignmentDetails struct {
+	TeamName           string                `json:"team_name"`
+	PoolTeam           string                `json:"pool_team"`
+	SelectionMode      string                `json:"selection_mode"`
+	PreferWorkingHours bool                  `json:"prefer_working_hours"`
+	Candidates         []string              `json:"candidates"`
+	Exclusions         []AssignmentExclusion `json:"exclusions"`
+	Picks              []AssignmentPick      `json:"picks"`
+}
+
+type AssignmentExclusion struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Reason   string `json:"reason"`
+}
+
+type AssignmentPick struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// ReviewerChangeDetails — details событий reassigned и released.
+type ReviewerChangeDetails struct {
+	OldUserID string `json:"old_user_id"`
+	NewUserID string `json:"new_user_id,omitempty"`
+}
+
+func AssignmentDetailsFromDomain(e pr.AssignmentExplanation) AssignmentDetails {
+	d := AssignmentDetails{
+		TeamName:           e.TeamName,
+		PoolTeam:           e.PoolTeam,
+		SelectionMode:      string(e.SelectionMode),
+		PreferWorkingHours: e.PreferWorkingHours,
+		Candidates:         append([]string{}, e.Candidates...),
+		Exclusions:         make([]AssignmentExclusion, 0, len(e.Exclusions)),
+		Picks:              make([]AssignmentPick, 0, len(e.Picks)),
+	}
+	for _, x := range e.Exclusions {
+		d.Exclusions = append(d.Exclusions, AssignmentExclusion{UserID: x.UserId, TeamName: x.TeamName, Reason: string(x.Reason)})
+	}
+	for _, p := range e.Picks {
+		d.Picks = append(d.Picks, AssignmentPick{UserID: p.UserId, Reason: string(p.Reason)})
+	}
+	return d
+}
+
+// ToDomain заполняет обоснование, кроме полей самой записи истории.
+func (d AssignmentDetails) ToDomain() pr.AssignmentExplanation {
+	e := pr.AssignmentExplanation{
+		TeamName:           d.TeamName,
+		PoolTeam:           d.PoolTeam,
+		SelectionMode:      pr.SelectionMode(d.SelectionMode),
+		PreferWorkingHours: d.PreferWorkingHours,
+		Candidates:         append([]string{}, d.Candidates...),
+		Exclusions:         make([]pr.AssignmentExclusion, 0, len(d.Exclusions)),
+		Picks:              make([]pr.AssignmentPick, 0, len(d.Picks)),
+	}
+	for _, x := range d.Exclusions {
+		e.Exclusions = append(e.Exclusions, pr.AssignmentExclusion{UserId: x.UserID, TeamName: x.TeamName, Reason: pr.ExclusionReason(x.Reason)})
+	}
+	for _, p := range d.Picks {
+		e.Picks = append(e.Picks, pr.AssignmentPick{UserId: p.UserID, Reason: pr.PickReason(p.Reason)})
+	}
+	return e
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"errors"
+	"pr-service/internal/domain/pr"
 	"pr-service/internal/infrastructure/http/openapi"
 )
 
@@ -15,12 +16,18 @@ var (
 type codedError struct {
 	code    openapi.ErrorResponseErrorCode
 	message string
+	// base — доменная ошибка, которой соответствует код (может быть nil)
+	base error
 }
 
 func (e codedError) Error() string {
 	return e.message
 }
 
+func (e codedError) Unwrap() error {
+	return e.base
+}
+
 func (e codedError) Code() openapi.ErrorResponseErrorCode {
 	return e.code
 }
@@ -29,6 +36,12 @@ var (
 	ErrNoCandidate = codedError{
 		code:    openapi.NOCANDIDATE,
 		message: "нет доступных ревьюеров в команде",
+		base:    pr.ErrNoCandidate,
+	}
+	ErrAllAtCapacity = codedError{
+		code:    openapi.ALLATCAPACITY,
+		message: "у всех доступных ревьюеров исчерпан лимит открытых ревью",
+		base:    pr.ErrAllAtCapacity,
 	}
 	ErrNotFound = codedError{
 		code:    openapi.NOTFOUND,
@@ -37,6 +50,7 @@ var (
 	ErrPrExists = codedError{
 		code:    openapi.PREXISTS,
 		message: "pull request уже существует",
+		base:    pr.ErrPullRequestExists,
 	}
 	ErrAlreadyMerged = codedError{
 		code:    openapi.NOTASSIGNED,
@@ -46,6 +60,28 @@ var (
 		code:    openapi.PRMERGED,
 		message: "у пользователя нет команды",
 	}
+	ErrVersionMismatch = codedError{
+		code:    openapi.PRECONDITIONFAILED,
+		message: "pull request изменён: версия не совпадает с If-Match",
+		base:    pr.ErrVersionMismatch,
+	}
+	ErrConcurrentUpdate = codedError{
+		code:    openapi.CONCURRENTUPDATE,
+		message: "pull request одновременно изменяется другим запросом, повторите позже",
+	}
+	ErrUserAnonymized = codedError{
+		code:    openapi.USERANONYMIZED,
+		message: "пользователь анонимизирован",
+	}
+	ErrTeamCycle = codedError{
+		code:    openapi.TEAMCYCLE,
+		message: "родительская команда входит в поддерево команды",
+	}
+	ErrNotTeamLead = codedError{
+		code:    openapi.NOTTEAMLEAD,
+		message: "пользователь не является лидом команды",
+		base:    pr.ErrNotTeamLead,
+	}
 	ErrTeamExists = codedError{
 		code:    openapi.TEAMEXISTS,
 		message: "Команда существует",
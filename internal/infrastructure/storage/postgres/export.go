@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// streamRows читает результат запроса курсором sql.Rows и передаёт строки
+// в fn по одной: в памяти одновременно только текущая строка.
+func streamRows[T any](q *gorm.DB, fn func(*T) error) error {
+	rows, err := q.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		// новая переменная на строку: ToDomain берёт адреса её полей
+		var row T
+		if err := q.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (p *PostgresStorage) ExportPullRequests(ctx context.Context, f pr.PullRequestsFilter, fn func(pr.PullRequest) error) error {
+	const op = "storage.postgres.ExportPullRequests"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	q := p.db.WithContext(ctx).Table("pull_requests p").
+		Select(`p.*, ARRAY(
+			SELECT r.user_id FROM pull_request_reviewers r
+			WHERE r.org_id = p.org_id AND r.pull_request_id = p.pull_request_id
+			ORDER BY r.user_id
+		) AS reviewers`).
+		Where("p.org_id = ?", auth.OrgID(ctx))
+	if f.Status != nil {
+		q = q.Where("p.status = ?", *f.Status)
+	}
+	if f.TeamName != nil {
+		q = q.Where("p.team_name = ?", *f.TeamName)
+	}
+	if f.AuthorId != nil {
+		q = q.Where("p.author_id = ?", *f.AuthorId)
+	}
+	if f.CreatedFrom != nil {
+		q = q.Where("p.created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		q = q.Where("p.created_at < ?", *f.CreatedTo)
+	}
+
+	type row struct {
+		pgdto.PullRequest
+		Reviewers pq.StringArray `gorm:"column:reviewers"`
+	}
+	if err := streamRows(q.Order("p.pull_request_id"), func(r *row) error {
+		r.AssignedReviewers = r.Reviewers
+		return fn(r.ToDomain())
+	}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (p *PostgresStorage) ExportUsers(ctx context.Context, f pr.UsersListFilter, fn func(pr.User) error) error {
+	const op = "storage.postgres.ExportUsers"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	// команды и навыки собираются в той же строке, а не догружаются пачками,
+	// как в UsersList
+	q := p.db.WithContext(ctx).Table("users u").
+		Select(`u.*,
+			ARRAY(
+				SELECT m.team_name FROM team_memberships m
+				WHERE m.org_id = u.org_id AND m.user_id = u.user_id
+				ORDER BY m.team_name
+			) AS teams,
+			ARRAY(
+				SELECT s.tag FROM user_skills s
+				WHERE s.org_id = u.org_id AND s.user_id = u.user_id
+				ORDER BY s.tag
+			) AS skill_tags,
+			ARRAY(
+				SELECT s.level FROM user_skills s
+				WHERE s.org_id = u.org_id AND s.user_id = u.user_id
+				ORDER BY s.tag
+			) AS skill_levels`).
+		Where("u.org_id = ?", auth.OrgID(ctx))
+	if f.TeamName != nil {
+		q = q.Where(`EXISTS (
+			SELECT 1 FROM team_memberships m
+			WHERE m.org_id = u.org_id AND m.user_id = u.user_id AND m.team_name = ?
+		)`, *f.TeamName)
+	}
+	if f.IsActive != nil {
+		q = q.Where("u.is_active = ?", *f.IsActive)
+	}
+	if f.After != "" {
+		q = q.Where("u.user_id > ?", f.After)
+	}
+
+	type row struct {
+		pgdto.UserModel
+		Teams       pq.StringArray `gorm:"column:teams"`
+		SkillTags   pq.StringArray `gorm:"column:skill_tags"`
+		SkillLevels pq.StringArray `gorm:"column:skill_levels"`
+	}
+	if err := streamRows(q.Order("u.user_id"), func(r *row) error {
+		user := r.ToDomain()
+		user.Teams = append([]string{}, r.Teams...)
+		user.Skills = make([]pr.Skill, 0, len(r.SkillTags))
+		for i, tag := range r.SkillTags {
+			user.Skills = append(user.Skills, pr.Skill{Tag: tag, Level: pr.SkillLevel(r.SkillLevels[i])})
+		}
+		return fn(user)
+	}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (p *PostgresStorage) ExportTeams(ctx context.Context, root string, fn func(pr.TeamExport) error) error {
+	const op = "storage.postgres.ExportTeams"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+	org := auth.OrgID(ctx)
+
+	query := `
+		SELECT t.*,
+		       (SELECT COUNT(*) FROM team_memberships m
+		        WHERE m.org_id = t.org_id AND m.team_name = t.team_name) AS members,
+		       (SELECT COUNT(*) FROM team_memberships m
+		        JOIN users u ON u.org_id = m.org_id AND u.user_id = m.user_id
+		        WHERE m.org_id = t.org_id AND m.team_name = t.team_name
+		          AND m.is_active AND u.is_active) AS active_members
+		FROM teams t
+	`
+	var q *gorm.DB
+	if root == "" {
+		q = db.Raw(query+`
+			WHERE t.org_id = ?
+			ORDER BY t.team_name
+		`, org)
+	} else {
+		// корень поддерева проверяется заранее: после начала выгрузки
+		// сообщить об ошибке клиенту уже нельзя
+		if _, err := loadTeam(db, org, root); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		q = db.Raw(subtreeCTE+query+`
+			JOIN subtree s ON s.team_name = t.team_name
+			WHERE t.org_id = ?
+			ORDER BY s.depth, t.team_name
+		`, org, root, org, maxTeamDepth, org)
+	}
+
+	type row struct {
+		pgdto.TeamModel
+		Members       int `gorm:"column:members"`
+		ActiveMembers int `gorm:"column:active_members"`
+	}
+	if err := streamRows(q, func(r *row) error {
+		team := pr.TeamExport{
+			TeamName:           r.TeamName,
+			SelectionMode:      pr.SelectionMode(r.SelectionMode),
+			PreferWorkingHours: r.PreferWorkingHours,
+			Members:            r.Members,
+			ActiveMembers:      r.ActiveMembers,
+		}
+		// у корня поддерева родитель за пределами выгрузки
+		if r.ParentTeam != nil && r.TeamName != root {
+			team.ParentTeam = *r.ParentTeam
+		}
+		if r.MaxOpenReviews != nil {
+			team.MaxOpenReviews = *r.MaxOpenReviews
+		}
+		return fn(team)
+	}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
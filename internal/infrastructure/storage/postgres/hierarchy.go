@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+
+	"gorm.io/gorm"
+)
+
+// maxTeamDepth ограничивает рекурсивные запросы по иерархии на случай,
+// если цикл всё же попал в таблицу в обход приложения.
+const maxTeamDepth = 32
+
+// subtreeCTE — команда и все её потомки. Параметры: org, team_name, org, maxTeamDepth.
+const subtreeCTE = `
+	WITH RECURSIVE subtree AS (
+	    SELECT team_name, 0 AS depth
+	    FROM teams
+	    WHERE org_id = ? AND team_name = ?
+	  UNION ALL
+	    SELECT t.team_name, s.depth + 1
+	    FROM teams t
+	    JOIN subtree s ON t.parent_team = s.team_name
+	    WHERE t.org_id = ? AND s.depth < ?
+	)
+`
+
+// chainCTE — команда и её предки, depth 0 — сама команда.
+// Параметры: org, team_name, org, maxTeamDepth.
+const chainCTE = `
+	WITH RECURSIVE chain AS (
+	    SELECT team_name, parent_team, 0 AS depth
+	    FROM teams
+	    WHERE org_id = ? AND team_name = ?
+	  UNION ALL
+	    SELECT t.team_name, t.parent_team, c.depth + 1
+	    FROM teams t
+	    JOIN chain c ON t.team_name = c.parent_team
+	    WHERE t.org_id = ? AND c.depth < ?
+	)
+`
+
+func (p *PostgresStorage) TeamAncestors(ctx context.Context, teamName string) ([]string, error) {
+	const op = "storage.postgres.TeamAncestors"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var ancestors []string
+	if err := p.db.WithContext(ctx).Raw(chainCTE+`
+		SELECT team_name FROM chain WHERE depth > 0 ORDER BY depth
+	`, org, teamName, org, maxTeamDepth).Scan(&ancestors).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return ancestors, nil
+}
+
+func (p *PostgresStorage) TeamSetParent(ctx context.Context, r pr.TeamSetParent) (pr.Team, error) {
+	const op = "storage.postgres.TeamSetParent"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var team pr.Team
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		if err := lockTeamTree(tx, org); err != nil {
+			return err
+		}
+		if err := lockTeam(tx, org, r.TeamName); err != nil {
+			return err
+		}
+		if r.ParentTeam != "" {
+			if err := checkParent(tx, org, r.TeamName, r.ParentTeam); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&pgdto.TeamModel{}).
+			Where("org_id = ? AND team_name = ?", org, r.TeamName).
+			Update("parent_team", nullIfEmpty(r.ParentTeam)).Error; err != nil {
+			return err
+		}
+
+		var err error
+		team, err = loadTeam(tx, org, r.TeamName)
+		return err
+	})
+	if err != nil {
+		return pr.Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return team, nil
+}
+
+func (p *PostgresStorage) TeamTree(ctx context.Context, root string) ([]pr.TeamNode, error) {
+	const op = "storage.postgres.TeamTree"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+	org := auth.OrgID(ctx)
+
+	var rows []struct {
+		TeamName   string
+		ParentTeam *string
+		Members    int
+	}
+	query := `
+		SELECT t.team_name, t.parent_team,
+		       (SELECT COUNT(*) FROM team_memberships m
+		        WHERE m.org_id = t.org_id AND m.team_name = t.team_name) AS members
+		FROM teams t
+	`
+	var err error
+	if root == "" {
+		err = db.Raw(query+`
+			WHERE t.org_id = ?
+			ORDER BY t.team_name
+		`, org).Scan(&rows).Error
+	} else {
+		err = db.Raw(subtreeCTE+query+`
+			JOIN subtree s ON s.team_name = t.team_name
+			WHERE t.org_id = ?
+			ORDER BY s.depth, t.team_name
+		`, org, root, org, maxTeamDepth, org).Scan(&rows).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if root != "" && len(rows) == 0 {
+		return nil, ErrNotFound
+	}
+
+	nodes := make([]pr.TeamNode, 0, len(rows))
+	for _, row := range rows {
+		node := pr.TeamNode{TeamName: row.TeamName, Members: row.Members}
+		// у корня поддерева родитель за пределами ответа
+		if row.ParentTeam != nil && row.TeamName != root {
+			node.ParentTeam = *row.ParentTeam
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func (p *PostgresStorage) TeamStats(ctx context.Context, teamName string) (pr.TeamStats, error) {
+	const op = "storage.postgres.TeamStats"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+	org := auth.OrgID(ctx)
+
+	var teams []pr.TeamStatsRow
+	if err := db.Raw(subtreeCTE+`
+		SELECT s.team_name,
+		       (SELECT COUNT(*) FROM team_memberships m
+		        WHERE m.org_id = ? AND m.team_name = s.team_name) AS members,
+		       (SELECT COUNT(*) FROM team_memberships m
+		        JOIN users u ON u.org_id = m.org_id AND u.user_id = m.user_id
+		        WHERE m.org_id = ? AND m.team_name = s.team_name
+		          AND m.is_active AND u.is_active) AS active_members,
+		       (SELECT COUNT(*) FROM pull_requests p
+		        WHERE p.org_id = ? AND p.team_name = s.team_name AND p.status = 'OPEN') AS open_pull_requests,
+		       (SELECT COUNT(*) FROM pull_requests p
+		        WHERE p.org_id = ? AND p.team_name = s.team_name AND p.status = 'MERGED') AS merged_pull_requests,
+		       (SELECT COUNT(*) FROM pull_request_reviewers r
+		        JOIN pull_requests p ON p.org_id = r.org_id AND p.pull_request_id = r.pull_request_id
+		        WHERE p.org_id = ? AND p.team_name = s.team_name AND p.status = 'OPEN') AS open_reviews
+		FROM subtree s
+		ORDER BY s.depth, s.team_name
+	`, org, teamName, org, maxTeamDepth, org, org, org, org, org).Scan(&teams).Error; err != nil {
+		return pr.TeamStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(teams) == 0 {
+		return pr.TeamStats{}, ErrNotFound
+	}
+
+	// PR принадлежит одной команде, поэтому их можно сложить; участников
+	// нескольких команд поддерева нужно считать один раз
+	total := pr.TeamStatsRow{TeamName: teamName}
+	for _, t := range teams {
+		total.OpenPullRequests += t.OpenPullRequests
+		total.MergedPullRequests += t.MergedPullRequests
+		total.OpenReviews += t.OpenReviews
+	}
+	var members struct {
+		Members       int
+		ActiveMembers int
+	}
+	if err := db.Raw(subtreeCTE+`
+		SELECT COUNT(DISTINCT m.user_id) AS members,
+		       COUNT(DISTINCT m.user_id) FILTER (WHERE m.is_active AND u.is_active) AS active_members
+		FROM team_memberships m
+		JOIN subtree s ON s.team_name = m.team_name
+		JOIN users u ON u.org_id = m.org_id AND u.user_id = m.user_id
+		WHERE m.org_id = ?
+	`, org, teamName, org, maxTeamDepth, org).Scan(&members).Error; err != nil {
+		return pr.TeamStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	total.Members = members.Members
+	total.ActiveMembers = members.ActiveMembers
+
+	return pr.TeamStats{Total: total, Teams: teams}, nil
+}
+
+// lockTeamTree сериализует изменения иерархии внутри организации: две
+// параллельные транзакции не смогут по отдельности пройти проверку и вместе
+// образовать цикл.
+func lockTeamTree(tx *gorm.DB, org string) error {
+	return tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('team_tree:' || ?))`, org).Error
+}
+
+// checkParent проверяет, что parent существует и не входит в поддерево team.
+func checkParent(tx *gorm.DB, org, team, parent string) error {
+	var exists int64
+	if err := tx.Model(&pgdto.TeamModel{}).
+		Where("org_id = ? AND team_name = ?", org, parent).
+		Count(&exists).Error; err != nil {
+		return err
+	}
+	if exists == 0 {
+		return ErrNotFound
+	}
+
+	var cycle int64
+	if err := tx.Raw(chainCTE+`
+		SELECT COUNT(*) FROM chain WHERE team_name = ?
+	`, org, parent, org, maxTeamDepth, team).Scan(&cycle).Error; err != nil {
+		return err
+	}
+	if cycle > 0 {
+		return ErrTeamCycle
+	}
+	return nil
+}
+
+// loadTeam возвращает команду с родителем и участниками.
+func loadTeam(tx *gorm.DB, org, teamName string) (pr.Team, error) {
+	var models []pgdto.TeamModel
+	if err := tx.Where("org_id = ? AND team_name = ?", org, teamName).
+		Limit(1).
+		Find(&models).Error; err != nil {
+		return pr.Team{}, err
+	}
+	if len(models) == 0 {
+		return pr.Team{}, ErrNotFound
+	}
+
+	members, err := teamMembers(tx, org, teamName)
+	if err != nil {
+		return pr.Team{}, err
+	}
+	team := pr.Team{
+		TeamName:           teamName,
+		Members:            members,
+		SelectionMode:      pr.SelectionMode(models[0].SelectionMode),
+		PreferWorkingHours: models[0].PreferWorkingHours,
+	}
+	if models[0].ParentTeam != nil {
+		team.ParentTeam = *models[0].ParentTeam
+	}
+	if models[0].MaxOpenReviews != nil {
+		team.MaxOpenReviews = *models[0].MaxOpenReviews
+	}
+	return team, nil
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReviewerExclusions возвращает участников команды, которые не могут стать
+// ревьюверами PR автора, с первой подходящей причиной.
+func (p *PostgresStorage) ReviewerExclusions(ctx context.Context, teamName, authorID string) ([]pr.AssignmentExclusion, error) {
+	const op = "storage.postgres.ReviewerExclusions"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var rows []struct {
+		UserID string
+		Reason string
+	}
+	if err := p.db.WithContext(ctx).Raw(`
+		SELECT user_id, reason FROM (
+			SELECT u.user_id,
+			       CASE
+			         WHEN u.user_id = ? THEN 'author'
+			         WHEN NOT u.is_active THEN 'inactive'
+			         WHEN NOT m.is_active THEN 'inactive_in_team'
+			         WHEN `+atCapacityCond(ctx)+` THEN 'at_capacity'
+			       END AS reason
+			FROM users u
+			JOIN team_memberships m ON m.org_id = u.org_id AND m.user_id = u.user_id
+			JOIN teams t ON t.org_id = m.org_id AND t.team_name = m.team_name
+			WHERE u.org_id = ? AND m.team_name = ?
+		) x
+		WHERE reason IS NOT NULL
+		ORDER BY user_id
+	`, authorID, auth.OrgID(ctx), teamName).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	exclusions := make([]pr.AssignmentExclusion, 0, len(rows))
+	for _, row := range rows {
+		exclusions = append(exclusions, pr.AssignmentExclusion{
+			UserId:   row.UserID,
+			TeamName: teamName,
+			Reason:   pr.ExclusionReason(row.Reason),
+		})
+	}
+	return exclusions, nil
+}
+
+// AssignmentExplanation собирает обоснование подбора из истории PR.
+// PR, созданные до появления истории, дают ErrNotFound.
+func (p *PostgresStorage) AssignmentExplanation(ctx context.Context, prID string) (pr.AssignmentExplanation, error) {
+	const op = "storage.postgres.AssignmentExplanation"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var rows []pgdto.PullRequestHistoryModel
+	if err := p.db.WithContext(ctx).
+		Where("org_id = ? AND pull_request_id = ?", auth.OrgID(ctx), prID).
+		Order("id").
+		Find(&rows).Error; err != nil {
+		return pr.AssignmentExplanation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rows) == 0 || rows[0].Event != string(pr.HistoryCreated) {
+		return pr.AssignmentExplanation{}, fmt.Errorf("%s: %w", op, ErrNotFound)
+	}
+
+	var details pgdto.AssignmentDetails
+	if err := json.Unmarshal(rows[0].Details, &details); err != nil {
+		return pr.AssignmentExplanation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	explanation := details.ToDomain()
+	explanation.PullRequestId = prID
+	explanation.CreatedAt = rows[0].CreatedAt
+	if rows[0].Seed != nil {
+		explanation.Seed = *rows[0].Seed
+	}
+	if rows[0].AssignedAt != nil {
+		explanation.AssignedAt = *rows[0].AssignedAt
+	}
+
+	explanation.Changes = make([]pr.ReviewerChange, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		var change pgdto.ReviewerChangeDetails
+		if err := json.Unmarshal(row.Details, &change); err != nil {
+			return pr.AssignmentExplanation{}, fmt.Errorf("%s: %w", op, err)
+		}
+		explanation.Changes = append(explanation.Changes, pr.ReviewerChange{
+			Event:     pr.HistoryEvent(row.Event),
+			OldUserId: change.OldUserID,
+			NewUserId: change.NewUserID,
+			At:        row.CreatedAt,
+		})
+	}
+	return explanation, nil
+}
+
+// recordHistory добавляет событие в историю назначений PR; seed и
+// assignedAt есть только у события created.
+func recordHistory(tx *gorm.DB, org, prID string, event pr.HistoryEvent, seed *int64, assignedAt *time.Time, details any) error {
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	return tx.Exec(`
+		INSERT INTO pull_request_history (org_id, pull_request_id, event, seed, assigned_at, details)
+		VALUES (?, ?, ?, ?, ?, ?::jsonb)
+	`, org, prID, string(event), seed, assignedAt, string(raw)).Error
+}
+
+// recordReviewerChange записывает замену ревьювера; пустой newUserID —
+// ревьювер снят без замены.
+func recordReviewerChange(tx *gorm.DB, org, prID string, event pr.HistoryEvent, oldUserID, newUserID string) error {
+	return recordHistory(tx, org, prID, event, nil, nil, pgdto.ReviewerChangeDetails{
+		OldUserID: oldUserID,
+		NewUserID: newUserID,
+	})
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-service/internal/domain/idempotency"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+	"time"
+)
+
+func (p *PostgresStorage) IdempotencyReserve(ctx context.Context, rec idempotency.Record) (idempotency.Record, bool, error) {
+	const op = "storage.postgres.IdempotencyReserve"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+
+	// истёкшая запись и незавершённая с истёкшей арендой (процесс упал, не
+	// освободив ключ) перезаписываются, как если бы их не было
+	res := db.Exec(`
+		INSERT INTO idempotency_keys (org_id, subject, idem_key, request_hash, created_at, expires_at, locked_until)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (org_id, subject, idem_key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+		    status_code = NULL,
+		    content_type = NULL,
+		    etag = NULL,
+		    response_body = NULL,
+		    created_at = EXCLUDED.created_at,
+		    expires_at = EXCLUDED.expires_at,
+		    locked_until = EXCLUDED.locked_until
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+		   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= EXCLUDED.created_at)
+	`, rec.OrgID, rec.Subject, rec.Key, rec.RequestHash, rec.CreatedAt, rec.ExpiresAt, rec.LockedUntil)
+	if res.Error != nil {
+		return idempotency.Record{}, false, fmt.Errorf("%s: %w", op, res.Error)
+	}
+	if res.RowsAffected > 0 {
+		return rec, true, nil
+	}
+
+	var model pgdto.IdempotencyKeyModel
+	if err := db.Where("org_id = ? AND subject = ? AND idem_key = ?", rec.OrgID, rec.Subject, rec.Key).
+		First(&model).Error; err != nil {
+		return idempotency.Record{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+	return model.ToDomain(), false, nil
+}
+
+func (p *PostgresStorage) IdempotencyComplete(ctx context.Context, rec idempotency.Record) error {
+	const op = "storage.postgres.IdempotencyComplete"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	// created_at отличает эту резервацию от перехватившей ключ после аренды
+	err := p.db.WithContext(ctx).
+		Model(&pgdto.IdempotencyKeyModel{}).
+		Where("org_id = ? AND subject = ? AND idem_key = ? AND created_at = ? AND status_code IS NULL",
+			rec.OrgID, rec.Subject, rec.Key, rec.CreatedAt).
+		Updates(map[string]any{
+			"status_code":   rec.StatusCode,
+			"content_type":  rec.ContentType,
+			"etag":          rec.ETag,
+			"response_body": rec.Body,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (p *PostgresStorage) IdempotencyRelease(ctx context.Context, rec idempotency.Record) error {
+	const op = "storage.postgres.IdempotencyRelease"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	err := p.db.WithContext(ctx).
+		Where("org_id = ? AND subject = ? AND idem_key = ? AND created_at = ? AND status_code IS NULL",
+			rec.OrgID, rec.Subject, rec.Key, rec.CreatedAt).
+		Delete(&pgdto.IdempotencyKeyModel{}).Error
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (p *PostgresStorage) IdempotencyDeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const op = "storage.postgres.IdempotencyDeleteExpired"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	res := p.db.WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Delete(&pgdto.IdempotencyKeyModel{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("%s: %w", op, res.Error)
+	}
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+
+	"gorm.io/gorm"
+)
+
+// TeamLeadReviewers возвращает лидов команды с правилами. Флаг членства
+// не учитывается: лид, выключивший себя из очереди ревью, всё равно
+// получает PR по своим правилам; выключенные, анонимизированные и лиды
+// с исчерпанным лимитом открытых ревью — нет.
+func (p *PostgresStorage) TeamLeadReviewers(ctx context.Context, teamName string) ([]pr.LeadReviewer, error) {
+	const op = "storage.postgres.TeamLeadReviewers"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var rows []pgdto.TeamLeadRuleModel
+	if err := p.db.WithContext(ctx).Raw(`
+		SELECT r.org_id, r.team_name, r.user_id, r.kind, r.value
+		FROM team_lead_rules r
+		JOIN team_memberships m
+		  ON m.org_id = r.org_id AND m.team_name = r.team_name AND m.user_id = r.user_id
+		JOIN users u ON u.org_id = r.org_id AND u.user_id = r.user_id
+		JOIN teams t ON t.org_id = m.org_id AND t.team_name = m.team_name
+		WHERE r.org_id = ? AND r.team_name = ?
+		  AND m.role = 'lead'
+		  AND u.is_active = true
+		  AND u.anonymized_at IS NULL
+		  AND NOT `+atCapacityCond(ctx)+`
+		ORDER BY r.user_id, r.kind, r.value
+	`, auth.OrgID(ctx), teamName).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var leads []pr.LeadReviewer
+	for _, row := range rows {
+		if len(leads) == 0 || leads[len(leads)-1].UserId != row.UserID {
+			leads = append(leads, pr.LeadReviewer{UserId: row.UserID})
+		}
+		last := &leads[len(leads)-1]
+		last.Rules = append(last.Rules, row.ToDomain())
+	}
+	return leads, nil
+}
+
+func (p *PostgresStorage) TeamSetLeadRules(ctx context.Context, r pr.TeamSetLeadRules) (pr.Team, error) {
+	const op = "storage.postgres.TeamSetLeadRules"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var team pr.Team
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		if err := lockTeam(tx, org, r.TeamName); err != nil {
+			return err
+		}
+
+		var roles []string
+		if err := tx.Model(&pgdto.TeamMembershipModel{}).
+			Where("org_id = ? AND team_name = ? AND user_id = ?", org, r.TeamName, r.UserId).
+			Pluck("role", &roles).Error; err != nil {
+			return err
+		}
+		if len(roles) == 0 {
+			return ErrNotFound
+		}
+		if pr.MembershipRole(roles[0]) != pr.MembershipRoleLead {
+			return ErrNotTeamLead
+		}
+
+		if err := tx.Exec(`
+			DELETE FROM team_lead_rules
+			WHERE org_id = ? AND team_name = ? AND user_id = ?
+		`, org, r.TeamName, r.UserId).Error; err != nil {
+			return err
+		}
+		for _, rule := range r.Rules {
+			if err := tx.Exec(`
+				INSERT INTO team_lead_rules (org_id, team_name, user_id, kind, value)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT DO NOTHING
+			`, org, r.TeamName, r.UserId, string(rule.Kind), rule.Value).Error; err != nil {
+				return err
+			}
+		}
+
+		var err error
+		team, err = loadTeam(tx, org, r.TeamName)
+		return err
+	})
+	if err != nil {
+		return pr.Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return team, nil
+}
+
+// attachLeadRules заполняет LeadRules у лидов команды одним запросом.
+func attachLeadRules(tx *gorm.DB, org, teamName string, members []pr.TeamMember) error {
+	var rows []pgdto.TeamLeadRuleModel
+	if err := tx.Where("org_id = ? AND team_name = ?", org, teamName).
+		Order("kind, value").
+		Find(&rows).Error; err != nil {
+		return err
+	}
+	byID := make(map[string][]pr.LeadRule)
+	for _, row := range rows {
+		byID[row.UserID] = append(byID[row.UserID], row.ToDomain())
+	}
+	for i := range members {
+		if members[i].Role == pr.MembershipRoleLead {
+			members[i].LeadRules = byID[members[i].UserId]
+		}
+	}
+	return nil
+}
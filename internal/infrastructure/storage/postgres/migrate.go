@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Migrate выполняет команду goose (up, down, status) над каталогом
+// миграций. Отчёт goose пишет в свой логгер (по умолчанию stderr).
+func Migrate(ctx context.Context, cfg Config, command string) error {
+	const op = "storage.postgres.Migrate"
+
+	sqlDB, err := sql.Open("postgres", cfg.DSN)
+	if err != nil {
+		return fmt.Errorf("%s: %w: %w", op, ErrOpenDB, err)
+	}
+	defer sqlDB.Close()
+
+	if err := goose.RunContext(ctx, command, sqlDB, cfg.MigrationsPath); err != nil {
+		return fmt.Errorf("%s: %w: %w", op, ErrMigration, err)
+	}
+	return nil
+}
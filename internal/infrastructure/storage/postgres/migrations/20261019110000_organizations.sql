@@ -0,0 +1,87 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE organizations (
+    org_id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO organizations (org_id, name) VALUES ('default', 'Default organization');
+
+-- внешние ключи ссылаются на старые первичные ключи, поэтому снимаются первыми
+ALTER TABLE api_keys DROP CONSTRAINT api_keys_team_name_fkey;
+ALTER TABLE pull_request_reviewers DROP CONSTRAINT pull_request_reviewers_pull_request_id_fkey;
+ALTER TABLE pull_request_reviewers DROP CONSTRAINT pull_request_reviewers_user_id_fkey;
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_author_id_fkey;
+ALTER TABLE users DROP CONSTRAINT users_team_name_fkey;
+
+-- DEFAULT заполняет существующие строки организацией по умолчанию и сразу снимается:
+-- новые строки обязаны указывать org_id явно
+ALTER TABLE teams ADD COLUMN org_id TEXT NOT NULL DEFAULT 'default' REFERENCES organizations(org_id);
+ALTER TABLE users ADD COLUMN org_id TEXT NOT NULL DEFAULT 'default' REFERENCES organizations(org_id);
+ALTER TABLE pull_requests ADD COLUMN org_id TEXT NOT NULL DEFAULT 'default' REFERENCES organizations(org_id);
+ALTER TABLE pull_request_reviewers ADD COLUMN org_id TEXT NOT NULL DEFAULT 'default' REFERENCES organizations(org_id);
+ALTER TABLE api_keys ADD COLUMN org_id TEXT NOT NULL DEFAULT 'default' REFERENCES organizations(org_id);
+
+ALTER TABLE teams ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE pull_requests ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE pull_request_reviewers ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE api_keys ALTER COLUMN org_id DROP DEFAULT;
+
+ALTER TABLE teams DROP CONSTRAINT teams_pkey, ADD PRIMARY KEY (org_id, team_name);
+ALTER TABLE users DROP CONSTRAINT users_pkey, ADD PRIMARY KEY (org_id, user_id);
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_pkey, ADD PRIMARY KEY (org_id, pull_request_id);
+ALTER TABLE pull_request_reviewers DROP CONSTRAINT pull_request_reviewers_pkey,
+    ADD PRIMARY KEY (org_id, pull_request_id, user_id);
+
+ALTER TABLE users ADD CONSTRAINT users_team_fkey
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name);
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_author_fkey
+    FOREIGN KEY (org_id, author_id) REFERENCES users(org_id, user_id);
+ALTER TABLE pull_request_reviewers ADD CONSTRAINT pull_request_reviewers_pr_fkey
+    FOREIGN KEY (org_id, pull_request_id) REFERENCES pull_requests(org_id, pull_request_id) ON DELETE CASCADE;
+ALTER TABLE pull_request_reviewers ADD CONSTRAINT pull_request_reviewers_user_fkey
+    FOREIGN KEY (org_id, user_id) REFERENCES users(org_id, user_id) ON DELETE CASCADE;
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_team_fkey
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name);
+
+CREATE INDEX pull_request_reviewers_user_idx ON pull_request_reviewers (org_id, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- откат возможен, только если идентификаторы не пересекаются между организациями
+DROP INDEX pull_request_reviewers_user_idx;
+
+ALTER TABLE api_keys DROP CONSTRAINT api_keys_team_fkey;
+ALTER TABLE pull_request_reviewers DROP CONSTRAINT pull_request_reviewers_user_fkey;
+ALTER TABLE pull_request_reviewers DROP CONSTRAINT pull_request_reviewers_pr_fkey;
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_author_fkey;
+ALTER TABLE users DROP CONSTRAINT users_team_fkey;
+
+ALTER TABLE pull_request_reviewers DROP CONSTRAINT pull_request_reviewers_pkey,
+    ADD PRIMARY KEY (pull_request_id, user_id);
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_pkey, ADD PRIMARY KEY (pull_request_id);
+ALTER TABLE users DROP CONSTRAINT users_pkey, ADD PRIMARY KEY (user_id);
+ALTER TABLE teams DROP CONSTRAINT teams_pkey, ADD PRIMARY KEY (team_name);
+
+ALTER TABLE api_keys DROP COLUMN org_id;
+ALTER TABLE pull_request_reviewers DROP COLUMN org_id;
+ALTER TABLE pull_requests DROP COLUMN org_id;
+ALTER TABLE users DROP COLUMN org_id;
+ALTER TABLE teams DROP COLUMN org_id;
+
+ALTER TABLE users ADD CONSTRAINT users_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name);
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_author_id_fkey
+    FOREIGN KEY (author_id) REFERENCES users(user_id);
+ALTER TABLE pull_request_reviewers ADD CONSTRAINT pull_request_reviewers_pull_request_id_fkey
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE;
+ALTER TABLE pull_request_reviewers ADD CONSTRAINT pull_request_reviewers_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name);
+
+DROP TABLE organizations;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE idempotency_keys (
+    org_id TEXT NOT NULL REFERENCES organizations(org_id),
+    -- subject — principal запроса: один ключ у разных клиентов не пересекается
+    subject TEXT NOT NULL,
+    idem_key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    -- status_code и response_body заполняются после завершения запроса
+    status_code INT,
+    content_type TEXT,
+    etag TEXT,
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    -- locked_until — аренда незавершённого запроса: после неё ключ может занять повтор
+    locked_until TIMESTAMP NOT NULL,
+    PRIMARY KEY (org_id, subject, idem_key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE idempotency_keys;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- version увеличивается при каждом изменении PR и отдаётся клиенту как ETag
+ALTER TABLE pull_requests ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests DROP COLUMN version;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- переименование команды распространяется на участников и API-ключи
+ALTER TABLE users DROP CONSTRAINT users_team_fkey;
+ALTER TABLE users ADD CONSTRAINT users_team_fkey
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name) ON UPDATE CASCADE;
+
+ALTER TABLE api_keys DROP CONSTRAINT api_keys_team_fkey;
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_team_fkey
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name) ON UPDATE CASCADE;
+
+CREATE INDEX users_team_idx ON users (org_id, team_name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX users_team_idx;
+
+ALTER TABLE api_keys DROP CONSTRAINT api_keys_team_fkey;
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_team_fkey
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name);
+
+ALTER TABLE users DROP CONSTRAINT users_team_fkey;
+ALTER TABLE users ADD CONSTRAINT users_team_fkey
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name);
+-- +goose StatementEnd
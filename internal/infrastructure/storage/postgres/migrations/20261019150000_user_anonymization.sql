@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- анонимизированный пользователь остаётся в таблице: на user_id ссылаются
+-- pull_requests.author_id и pull_request_reviewers
+ALTER TABLE users ADD COLUMN anonymized_at TIMESTAMP NULL;
+
+CREATE INDEX users_active_idx ON users (org_id, is_active, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX users_active_idx;
+
+ALTER TABLE users DROP COLUMN anonymized_at;
+-- +goose StatementEnd
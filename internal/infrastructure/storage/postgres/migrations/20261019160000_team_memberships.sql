@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- пользователь может состоять в нескольких командах; users.team_name остаётся
+-- основной командой (пул ревьюверов по умолчанию при создании PR)
+CREATE TABLE team_memberships (
+    org_id TEXT NOT NULL,
+    team_name TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'lead')),
+    -- is_active — участвует ли пользователь в ревью этой команды
+    -- (общий флаг users.is_active действует на все команды)
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (org_id, team_name, user_id),
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (org_id, user_id) REFERENCES users(org_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX team_memberships_user_idx ON team_memberships (org_id, user_id);
+
+INSERT INTO team_memberships (org_id, team_name, user_id)
+SELECT org_id, team_name, user_id FROM users WHERE team_name IS NOT NULL;
+
+-- команда, из пула которой назначены ревьюверы PR
+ALTER TABLE pull_requests ADD COLUMN team_name TEXT NULL;
+
+UPDATE pull_requests p
+SET team_name = a.team_name
+FROM users a
+WHERE a.org_id = p.org_id AND a.user_id = p.author_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests DROP COLUMN team_name;
+
+DROP TABLE team_memberships;
+-- +goose StatementEnd
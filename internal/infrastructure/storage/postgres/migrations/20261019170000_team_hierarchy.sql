@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- команды образуют лес: отдел -> команда -> подкоманда. Циклы запрещает
+-- приложение (под advisory-блокировкой организации), CHECK ловит только петлю на себя
+ALTER TABLE teams ADD COLUMN parent_team TEXT NULL;
+ALTER TABLE teams ADD CONSTRAINT teams_parent_fkey
+    FOREIGN KEY (org_id, parent_team) REFERENCES teams(org_id, team_name) ON UPDATE CASCADE;
+ALTER TABLE teams ADD CONSTRAINT teams_parent_not_self CHECK (parent_team <> team_name);
+
+CREATE INDEX teams_parent_idx ON teams (org_id, parent_team);
+CREATE INDEX pull_requests_team_idx ON pull_requests (org_id, team_name, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX pull_requests_team_idx;
+DROP INDEX teams_parent_idx;
+
+ALTER TABLE teams DROP CONSTRAINT teams_parent_not_self;
+ALTER TABLE teams DROP CONSTRAINT teams_parent_fkey;
+ALTER TABLE teams DROP COLUMN parent_team;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- метки PR — один из критериев, по которым к PR добавляется лид
+ALTER TABLE pull_requests ADD COLUMN labels TEXT[] NOT NULL DEFAULT '{}';
+
+-- грейд пользователя; NULL — не указан
+ALTER TABLE users ADD COLUMN seniority TEXT NULL
+    CHECK (seniority IN ('junior', 'middle', 'senior'));
+
+-- правила, по которым лид команды добавляется ревьювером PR этой команды.
+-- Правило действует, пока у членства роль lead
+CREATE TABLE team_lead_rules (
+    org_id TEXT NOT NULL,
+    team_name TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('name_prefix', 'label', 'author_seniority', 'fallback')),
+    -- value пустое только у fallback
+    value TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (org_id, team_name, user_id, kind, value),
+    FOREIGN KEY (org_id, team_name, user_id)
+        REFERENCES team_memberships(org_id, team_name, user_id)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE team_lead_rules;
+
+ALTER TABLE users DROP COLUMN seniority;
+
+ALTER TABLE pull_requests DROP COLUMN labels;
+-- +goose StatementEnd
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- правила CODEOWNERS команды в порядке файла; при сопоставлении
+-- действует последнее подходящее правило
+CREATE TABLE team_codeowners (
+    org_id TEXT NOT NULL,
+    team_name TEXT NOT NULL,
+    position INT NOT NULL,
+    -- line — номер строки в загруженном файле, для сообщений
+    line INT NOT NULL,
+    pattern TEXT NOT NULL,
+    owner_users TEXT[] NOT NULL DEFAULT '{}',
+    owner_teams TEXT[] NOT NULL DEFAULT '{}',
+    PRIMARY KEY (org_id, team_name, position),
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE team_codeowners;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- навыки пользователя; по ним ревьюверы подбираются под required_skills PR
+CREATE TABLE user_skills (
+    org_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    level TEXT NOT NULL CHECK (level IN ('basic', 'intermediate', 'expert')),
+    PRIMARY KEY (org_id, user_id, tag),
+    FOREIGN KEY (org_id, user_id) REFERENCES users(org_id, user_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE user_skills;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- история пар автор–ревьювер: одна строка на назначение ревьювера на PR,
+-- сохраняется и после переназначения
+CREATE TABLE review_pairings (
+    org_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    reviewer_id TEXT NOT NULL,
+    assigned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (org_id, pull_request_id, reviewer_id),
+    FOREIGN KEY (org_id, pull_request_id) REFERENCES pull_requests(org_id, pull_request_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX review_pairings_author_idx ON review_pairings (org_id, author_id, assigned_at);
+
+INSERT INTO review_pairings (org_id, pull_request_id, author_id, reviewer_id, assigned_at)
+SELECT r.org_id, r.pull_request_id, p.author_id, r.user_id, p.created_at
+FROM pull_request_reviewers r
+JOIN pull_requests p ON p.org_id = r.org_id AND p.pull_request_id = r.pull_request_id;
+
+-- режим подбора ревьюверов при создании PR: random — случайно,
+-- spread — реже повторять недавние пары автор–ревьювер
+ALTER TABLE teams ADD COLUMN selection_mode TEXT NOT NULL DEFAULT 'random'
+    CHECK (selection_mode IN ('random', 'spread'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams DROP COLUMN selection_mode;
+
+DROP TABLE review_pairings;
+-- +goose StatementEnd
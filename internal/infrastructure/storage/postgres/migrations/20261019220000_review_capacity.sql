@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- сколько открытых PR пользователь может ревьюить одновременно; NULL — берётся
+-- лимит команды, через которую он назначается
+ALTER TABLE users ADD COLUMN max_open_reviews INTEGER NULL
+    CHECK (max_open_reviews > 0);
+
+-- лимит по умолчанию для участников команды; NULL — без ограничения
+ALTER TABLE teams ADD COLUMN max_open_reviews INTEGER NULL
+    CHECK (max_open_reviews > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams DROP COLUMN max_open_reviews;
+
+ALTER TABLE users DROP COLUMN max_open_reviews;
+-- +goose StatementEnd
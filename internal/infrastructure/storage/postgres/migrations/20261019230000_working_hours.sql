@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- рабочее время пользователя: часовой пояс IANA, начало и конец рабочего дня
+-- в минутах от полуночи по местному времени и рабочие дни (1 — понедельник,
+-- 7 — воскресенье). NULL в timezone — расписание не задано
+ALTER TABLE users
+    ADD COLUMN timezone TEXT NULL,
+    ADD COLUMN work_start SMALLINT NULL CHECK (work_start BETWEEN 0 AND 1439),
+    ADD COLUMN work_end SMALLINT NULL CHECK (work_end BETWEEN 1 AND 1440),
+    ADD COLUMN work_days SMALLINT[] NULL,
+    ADD CONSTRAINT users_working_hours_check CHECK (
+        (timezone IS NULL AND work_start IS NULL AND work_end IS NULL AND work_days IS NULL)
+        OR (timezone IS NOT NULL AND work_start < work_end AND work_days IS NOT NULL)
+    );
+
+-- предпочитать при создании PR тех, у кого сейчас рабочее время
+ALTER TABLE teams ADD COLUMN prefer_working_hours BOOLEAN NOT NULL DEFAULT false;
+
+-- момент назначения ревьювера — начало отсчёта SLA
+ALTER TABLE pull_request_reviewers ADD COLUMN assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+UPDATE pull_request_reviewers prr
+SET assigned_at = COALESCE(
+    (SELECT rp.assigned_at FROM review_pairings rp
+     WHERE rp.org_id = prr.org_id
+       AND rp.pull_request_id = prr.pull_request_id
+       AND rp.reviewer_id = prr.user_id),
+    (SELECT p.created_at FROM pull_requests p
+     WHERE p.org_id = prr.org_id AND p.pull_request_id = prr.pull_request_id),
+    NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_request_reviewers DROP COLUMN assigned_at;
+
+ALTER TABLE teams DROP COLUMN prefer_working_hours;
+
+ALTER TABLE users
+    DROP CONSTRAINT users_working_hours_check,
+    DROP COLUMN work_days,
+    DROP COLUMN work_end,
+    DROP COLUMN work_start,
+    DROP COLUMN timezone;
+-- +goose StatementEnd
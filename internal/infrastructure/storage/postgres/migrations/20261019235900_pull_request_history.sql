@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- история назначений ревьюверов PR: при создании записываются зерно
+-- случайного подбора, момент подбора и обоснование выбора, при заменах —
+-- кто кого сменил
+CREATE TABLE pull_request_history (
+    id BIGSERIAL PRIMARY KEY,
+    org_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    event TEXT NOT NULL CHECK (event IN ('created', 'reassigned', 'released')),
+    seed BIGINT,
+    -- от момента подбора считаются рабочее время и окно прошлых пар
+    assigned_at TIMESTAMPTZ,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (org_id, pull_request_id) REFERENCES pull_requests(org_id, pull_request_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX pull_request_history_pr_idx ON pull_request_history (org_id, pull_request_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE pull_request_history;
+-- +goose StatementEnd
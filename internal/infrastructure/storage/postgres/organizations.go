@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+
+	"gorm.io/gorm"
+)
+
+func (p *PostgresStorage) OrganizationCreate(ctx context.Context, org auth.Organization) error {
+	const op = "storage.postgres.OrganizationCreate"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		if err := tx.Model(&pgdto.OrganizationModel{}).
+			Where("org_id = ?", org.OrgID).
+			Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists > 0 {
+			return auth.ErrOrgExists
+		}
+
+		return tx.Create(&pgdto.OrganizationModel{
+			OrgID:     org.OrgID,
+			Name:      org.Name,
+			CreatedAt: org.CreatedAt,
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (p *PostgresStorage) OrganizationList(ctx context.Context) ([]auth.Organization, error) {
+	const op = "storage.postgres.OrganizationList"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var models []pgdto.OrganizationModel
+	if err := p.db.WithContext(ctx).Order("org_id").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	orgs := make([]auth.Organization, 0, len(models))
+	for _, m := range models {
+		orgs = append(orgs, m.ToDomain())
+	}
+	return orgs, nil
+}
+
+func (p *PostgresStorage) OrganizationExists(ctx context.Context, orgID string) (bool, error) {
+	const op = "storage.postgres.OrganizationExists"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var count int64
+	if err := p.db.WithContext(ctx).
+		Model(&pgdto.OrganizationModel{}).
+		Where("org_id = ?", orgID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (p *PostgresStorage) TeamSelection(ctx context.Context, teamName string) (pr.TeamSelection, error) {
+	const op = "storage.postgres.TeamSelection"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var models []pgdto.TeamModel
+	if err := p.db.WithContext(ctx).
+		Select("selection_mode", "prefer_working_hours").
+		Where("org_id = ? AND team_name = ?", auth.OrgID(ctx), teamName).
+		Limit(1).
+		Find(&models).Error; err != nil {
+		return pr.TeamSelection{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(models) == 0 {
+		return pr.TeamSelection{}, fmt.Errorf("%s: %w", op, ErrNotFound)
+	}
+	return pr.TeamSelection{
+		Mode:               pr.SelectionMode(models[0].SelectionMode),
+		PreferWorkingHours: models[0].PreferWorkingHours,
+	}, nil
+}
+
+func (p *PostgresStorage) TeamSetSelectionMode(ctx context.Context, r pr.TeamSetSelectionMode) (pr.Team, error) {
+	const op = "storage.postgres.TeamSetSelectionMode"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var team pr.Team
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		updates := map[string]any{}
+		if r.Mode != "" {
+			updates["selection_mode"] = string(r.Mode)
+		}
+		if r.PreferWorkingHours != nil {
+			updates["prefer_working_hours"] = *r.PreferWorkingHours
+		}
+		if len(updates) == 0 {
+			var err error
+			team, err = loadTeam(tx, org, r.TeamName)
+			return err
+		}
+
+		upd := tx.Model(&pgdto.TeamModel{}).
+			Where("org_id = ? AND team_name = ?", org, r.TeamName).
+			Updates(updates)
+		if upd.Error != nil {
+			return upd.Error
+		}
+		if upd.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		var err error
+		team, err = loadTeam(tx, org, r.TeamName)
+		return err
+	})
+	if err != nil {
+		return pr.Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return team, nil
+}
+
+func (p *PostgresStorage) PairHistory(ctx context.Context, authorID string, reviewerIDs []string, since time.Time) (map[string][]time.Time, error) {
+	const op = "storage.postgres.PairHistory"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	history := make(map[string][]time.Time)
+	if len(reviewerIDs) == 0 {
+		return history, nil
+	}
+	var rows []pgdto.ReviewPairingModel
+	if err := p.db.WithContext(ctx).
+		Where("org_id = ? AND author_id = ? AND reviewer_id IN ? AND assigned_at >= ?",
+			auth.OrgID(ctx), authorID, reviewerIDs, since).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, row := range rows {
+		history[row.ReviewerID] = append(history[row.ReviewerID], row.AssignedAt)
+	}
+	return history, nil
+}
+
+func (p *PostgresStorage) UserPairings(ctx context.Context, authorID string, since time.Time) ([]pr.Pairing, error) {
+	const op = "storage.postgres.UserPairings"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var rows []struct {
+		ReviewerID string
+		Count      int
+		LastAt     time.Time
+	}
+	if err := p.db.WithContext(ctx).Raw(`
+		SELECT reviewer_id, COUNT(*) AS count, MAX(assigned_at) AS last_at
+		FROM review_pairings
+		WHERE org_id = ? AND author_id = ? AND assigned_at >= ?
+		GROUP BY reviewer_id
+		ORDER BY count DESC, last_at DESC, reviewer_id
+	`, auth.OrgID(ctx), authorID, since).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pairings := make([]pr.Pairing, 0, len(rows))
+	for _, row := range rows {
+		pairings = append(pairings, pr.Pairing{
+			ReviewerId: row.ReviewerID,
+			Count:      row.Count,
+			LastAt:     row.LastAt,
+		})
+	}
+	return pairings, nil
+}
+
+// recordPairings записывает назначения ревьюверов в историю пар.
+// Повторное назначение того же ревьювера на тот же PR не учитывается.
+func recordPairings(tx *gorm.DB, org, prID, authorID string, reviewerIDs []string) error {
+	for _, id := range reviewerIDs {
+		if err := tx.Exec(`
+			INSERT INTO review_pairings (org_id, pull_request_id, author_id, reviewer_id)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT DO NOTHING
+		`, org, prID, authorID, id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,22 +2,32 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
+	"pr-service/internal/domain/auth"
 	"pr-service/internal/domain/pr"
 	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+	"slices"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/pressly/goose/v3"
+	"go.opentelemetry.io/otel"
 	gormpg "gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
+var tracer = otel.Tracer("pr-service/storage/postgres")
+
 type PostgresStorage struct {
 	db *gorm.DB
+	// expectedVersion — версия последней миграции в каталоге миграций
+	expectedVersion int64
 }
 
 func New(cfg Config, log *slog.Logger) (*PostgresStorage, error) {
@@ -34,15 +44,22 @@ func New(cfg Config, log *slog.Logger) (*PostgresStorage, error) {
 	sqlDB.SetMaxIdleConns(100)
 	sqlDB.SetConnMaxLifetime(time.Minute * 5)
 	sqlDB.SetConnMaxIdleTime(time.Minute)
-	log.Info("start migrate...", slog.String("path", cfg.MigrationsPath))
-	if err := goose.Up(sqlDB, cfg.MigrationsPath); err != nil {
+	if !cfg.SkipMigrations {
+		log.Info("start migrate...", slog.String("path", cfg.MigrationsPath))
+		if err := goose.Up(sqlDB, cfg.MigrationsPath); err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", op, ErrMigration, err)
+		}
+	}
+	expectedVersion, err := lastMigrationVersion(cfg.MigrationsPath)
+	if err != nil {
 		return nil, fmt.Errorf("%s: %w: %w", op, ErrMigration, err)
 	}
 	//DB seed
 	log.Info("start seeding...")
 
 	if cfg.Seed {
-		if err := SeedUsersWithTeams(sqlDB); err != nil {
+		// без principal-а — организация по умолчанию
+		if err := SeedUsersWithTeams(context.Background(), sqlDB); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 	}
@@ -53,18 +70,134 @@ func New(cfg Config, log *slog.Logger) (*PostgresStorage, error) {
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w: %w", op, ErrGormOpen, err)
 	}
+	// каждый SQL-запрос — отдельный span, вложенный в span метода хранилища
+	if err := gormDB.Use(tracing.NewPlugin(tracing.WithoutMetrics(), tracing.WithoutQueryVariables())); err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrGormOpen, err)
+	}
 
-	return &PostgresStorage{db: gormDB}, nil
+	return &PostgresStorage{db: gormDB, expectedVersion: expectedVersion}, nil
+}
+
+func lastMigrationVersion(dir string) (int64, error) {
+	migrations, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+	if err != nil {
+		return 0, err
+	}
+	last, err := migrations.Last()
+	if err != nil {
+		return 0, err
+	}
+	return last.Version, nil
+}
+
+// DB возвращает пул соединений (для метрик и служебных задач).
+func (p *PostgresStorage) DB() (*sql.DB, error) {
+	return p.db.DB()
+}
+
+// Ping проверяет доступность БД.
+func (p *PostgresStorage) Ping(ctx context.Context) error {
+	const op = "storage.postgres.Ping"
+
+	sqlDB, err := p.db.DB()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// MigrationVersion возвращает текущую версию схемы goose и ожидаемую версию
+// (последнюю миграцию, известную сервису).
+func (p *PostgresStorage) MigrationVersion(ctx context.Context) (current, expected int64, err error) {
+	const op = "storage.postgres.MigrationVersion"
+
+	sqlDB, err := p.db.DB()
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	current, err = goose.GetDBVersionContext(ctx, sqlDB)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return current, p.expectedVersion, nil
+}
+
+// Close закрывает пул соединений. Вызывается последним при остановке сервиса.
+func (p *PostgresStorage) Close() error {
+	const op = "storage.postgres.Close"
+
+	sqlDB, err := p.db.DB()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// nullIfEmpty превращает пустую строку в NULL для необязательных колонок.
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// findPullRequest загружает PR организации вместе с назначенными ревьюверами.
+func findPullRequest(tx *gorm.DB, org, id string) (pgdto.PullRequest, error) {
+	var prGorm pgdto.PullRequest
+	if err := tx.Where("org_id = ? AND pull_request_id = ?", org, id).
+		First(&prGorm).Error; err != nil {
+		return pgdto.PullRequest{}, err
+	}
+	if err := attachReviewers(tx, org, []*pgdto.PullRequest{&prGorm}); err != nil {
+		return pgdto.PullRequest{}, err
+	}
+	return prGorm, nil
+}
+
+// attachReviewers одним запросом заполняет AssignedReviewers у переданных PR.
+func attachReviewers(tx *gorm.DB, org string, prs []*pgdto.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(prs))
+	byID := make(map[string]*pgdto.PullRequest, len(prs))
+	for _, p := range prs {
+		ids = append(ids, p.PullRequestID)
+		byID[p.PullRequestID] = p
+	}
+
+	var rows []pgdto.PullRequestReviewer
+	if err := tx.Where("org_id = ? AND pull_request_id IN ?", org, ids).
+		Order("user_id").
+		Find(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		p := byID[row.PullRequestID]
+		p.AssignedReviewers = append(p.AssignedReviewers, row.UserID)
+	}
+	return nil
 }
 
 // PullRequestCreate — создаёт PR + сразу назначает ревьюеров (если переданы)
-func (p *PostgresStorage) PullRequestCreate(prEntity pr.PullRequest) error {
+func (p *PostgresStorage) PullRequestCreate(ctx context.Context, prEntity pr.PullRequest, explanation pr.AssignmentExplanation) error {
 	const op = "storage.postgres.PullRequestCreate"
 
-	return p.db.Transaction(func(tx *gorm.DB) error {
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+	org := auth.OrgID(ctx)
+
+	return db.Transaction(func(tx *gorm.DB) error {
 		var exists int64
 		if err := tx.Table("pull_requests").
-			Where("pull_request_id = ?", prEntity.PullRequestId).
+			Where("org_id = ? AND pull_request_id = ?", org, prEntity.PullRequestId).
 			Count(&exists).Error; err != nil {
 			return fmt.Errorf("%s: check existence failed: %w", op, err)
 		}
@@ -75,17 +208,31 @@ func (p *PostgresStorage) PullRequestCreate(prEntity pr.PullRequest) error {
 		if prEntity.CreatedAt == nil {
 			prEntity.CreatedAt = &now
 		}
+		if prEntity.Version == 0 {
+			prEntity.Version = pr.InitialVersion
+		}
+
+		// nil-массив записался бы как NULL, а колонка NOT NULL
+		labels := pq.StringArray(append([]string{}, prEntity.Labels...))
 
 		prToInsert := map[string]interface{}{
+			"org_id":            org,
 			"pull_request_id":   prEntity.PullRequestId,
 			"pull_request_name": prEntity.PullRequestName,
 			"author_id":         prEntity.AuthorId,
 			"status":            prEntity.Status,
 			"created_at":        prEntity.CreatedAt,
 			"merged_at":         prEntity.MergedAt,
+			"version":           prEntity.Version,
+			"team_name":         nullIfEmpty(prEntity.TeamName),
+			"labels":            labels,
 		}
 
 		if err := tx.Table("pull_requests").Create(prToInsert).Error; err != nil {
+			// параллельный запрос с тем же id успел вставить PR после проверки выше
+			if isUniqueViolation(err) {
+				return ErrPrExists
+			}
 			return fmt.Errorf("%s: failed to create pull_request: %w", op, err)
 		}
 
@@ -93,6 +240,7 @@ func (p *PostgresStorage) PullRequestCreate(prEntity pr.PullRequest) error {
 			records := make([]map[string]interface{}, 0, len(prEntity.AssignedReviewers))
 			for _, userID := range prEntity.AssignedReviewers {
 				records = append(records, map[string]interface{}{
+					"org_id":          org,
 					"pull_request_id": prEntity.PullRequestId,
 					"user_id":         userID,
 				})
@@ -102,171 +250,308 @@ func (p *PostgresStorage) PullRequestCreate(prEntity pr.PullRequest) error {
 				CreateInBatches(records, 100).Error; err != nil {
 				return fmt.Errorf("%s: failed to assign reviewers: %w", op, err)
 			}
+			if err := recordPairings(tx, org, prEntity.PullRequestId, prEntity.AuthorId, prEntity.AssignedReviewers); err != nil {
+				return fmt.Errorf("%s: failed to record pairings: %w", op, err)
+			}
+		}
+
+		details := pgdto.AssignmentDetailsFromDomain(explanation)
+		if err := recordHistory(tx, org, prEntity.PullRequestId, pr.HistoryCreated, &explanation.Seed, &explanation.AssignedAt, details); err != nil {
+			return fmt.Errorf("%s: failed to record history: %w", op, err)
 		}
 
 		return nil
 	})
 }
 
-// UsersSetIsActive — активирует пользователя
-func (p *PostgresStorage) UsersSetIsActive(userID string) error {
+// UsersSetIsActive — активирует или деактивирует пользователя
+func (p *PostgresStorage) UsersSetIsActive(ctx context.Context, userID string, isActive bool) error {
 	const op = "storage.postgres.UsersSetIsActive"
 
-	result := p.db.
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+
+	result := db.
 		Table("users").
-		Where("user_id = ?", userID).
-		Update("is_active", true)
+		Where("org_id = ? AND user_id = ? AND anonymized_at IS NULL", auth.OrgID(ctx), userID).
+		Update("is_active", isActive)
 
 	if result.Error != nil {
 		return fmt.Errorf("%s: %w", op, result.Error)
 	}
 
 	if result.RowsAffected == 0 {
+		var exists int64
+		if err := db.Table("users").
+			Where("org_id = ? AND user_id = ?", auth.OrgID(ctx), userID).
+			Count(&exists).Error; err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if exists > 0 {
+			return ErrUserAnonymized
+		}
 		return ErrNotFound
 	}
 
 	return nil
 }
 
-func (p *PostgresStorage) GetAuthorTeam(userID string) (string, error) {
+// GetAuthorTeam возвращает основную команду пользователя, а если её нет —
+// первую по имени из команд, в которых он состоит.
+func (p *PostgresStorage) GetAuthorTeam(ctx context.Context, userID string) (string, error) {
 	const op = "storage.postgres.GetAuthorTeam"
-	var teamName string
-	err := p.db.
-		Table("users").
-		Select("team_name").
-		Where("user_id = ?", userID).
-		Scan(&teamName).Error
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+	var teamName sql.NullString
+	err := db.Raw(`
+		SELECT COALESCE(u.team_name, (
+		    SELECT m.team_name FROM team_memberships m
+		    WHERE m.org_id = u.org_id AND m.user_id = u.user_id
+		    ORDER BY m.team_name
+		    LIMIT 1
+		))
+		FROM users u
+		WHERE u.org_id = ? AND u.user_id = ?
+	`, auth.OrgID(ctx), userID).Scan(&teamName).Error
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
+	return teamName.String, nil
+}
+
+// UserTeams возвращает команды пользователя в порядке имени.
+func (p *PostgresStorage) UserTeams(ctx context.Context, userID string) ([]string, error) {
+	const op = "storage.postgres.UserTeams"
 
-	return teamName, nil
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var teams []string
+	if err := p.db.WithContext(ctx).Model(&pgdto.TeamMembershipModel{}).
+		Where("org_id = ? AND user_id = ?", auth.OrgID(ctx), userID).
+		Order("team_name").
+		Pluck("team_name", &teams).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return teams, nil
 }
 
-func (p *PostgresStorage) GetFreeReviewers(teamName string, authorUserID string) ([]pr.User, error) {
+// GetFreeReviewers возвращает участников команды, которым можно назначить
+// ещё одно ревью: активных и не исчерпавших лимит открытых ревью. Если
+// активные есть, но все заняты, возвращается ErrAllAtCapacity.
+func (p *PostgresStorage) GetFreeReviewers(ctx context.Context, teamName string, authorUserID string) ([]pr.User, error) {
 	const op = "storage.postgres.GetFreeReviewers"
 
-	var users []pr.User
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+
+	var rows []struct {
+		UserID     string
+		Username   string
+		TeamName   string
+		IsActive   bool
+		AtCapacity bool
+		pgdto.WorkingHoursColumns
+	}
 
-	err := p.db.
-		Table("users").
-		Select(`
-			user_id AS user_id,
-			username AS username,
-			team_name AS team_name,
-			is_active AS is_active
-		`).
-		Where("team_name = ? AND user_id != ? AND is_active != ?", teamName, authorUserID, false).
-		Scan(&users).Error
+	// ревьювер должен быть активен и в целом, и в этой команде
+	err := db.Raw(`
+		SELECT u.user_id, u.username, m.team_name, u.is_active,
+		       u.timezone, u.work_start, u.work_end, u.work_days,
+		       `+atCapacityCond(ctx)+` AS at_capacity
+		FROM users u
+		JOIN team_memberships m ON m.org_id = u.org_id AND m.user_id = u.user_id
+		JOIN teams t ON t.org_id = m.org_id AND t.team_name = m.team_name
+		WHERE u.org_id = ?
+		  AND m.team_name = ?
+		  AND u.user_id != ?
+		  AND u.is_active = true
+		  AND m.is_active = true
+		ORDER BY u.user_id
+	`, auth.OrgID(ctx), teamName, authorUserID).Scan(&rows).Error
 
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if len(users) == 0 {
+	if len(rows) == 0 {
 		return nil, ErrNoCandidate
 	}
 
+	var users []pr.User
+	for _, row := range rows {
+		if row.AtCapacity {
+			continue
+		}
+		users = append(users, pr.User{
+			UserId:       row.UserID,
+			Username:     row.Username,
+			TeamName:     row.TeamName,
+			IsActive:     row.IsActive,
+			WorkingHours: row.WorkingHoursColumns.ToDomain(),
+		})
+	}
+	if len(users) == 0 {
+		return nil, ErrAllAtCapacity
+	}
+
 	return users, nil
 }
 
-func (p *PostgresStorage) PullRequestMerge(id string) (pr.PullRequest, error) {
+func (p *PostgresStorage) PullRequestGet(ctx context.Context, id string) (pr.PullRequest, error) {
+	const op = "storage.postgres.PullRequestGet"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	prGorm, err := findPullRequest(p.db.WithContext(ctx), auth.OrgID(ctx), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pr.PullRequest{}, ErrNotFound
+		}
+		return pr.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prGorm.ToDomain(), nil
+}
+
+func (p *PostgresStorage) PullRequestMerge(ctx context.Context, id string, expectedVersion int64) (pr.PullRequest, bool, error) {
 	const op = "storage.postgres.PullRequestMerge"
 
-	res := p.db.Model(&pgdto.PullRequest{}).
-		Where("pull_request_id = ? AND status = 'OPEN'", id).
-		Updates(map[string]any{
-			"status":    "MERGED",
-			"merged_at": gorm.Expr("NOW()"),
-		})
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+	org := auth.OrgID(ctx)
+
+	q := db.Model(&pgdto.PullRequest{}).
+		Where("org_id = ? AND pull_request_id = ? AND status = 'OPEN'", org, id)
+	if expectedVersion != 0 {
+		q = q.Where("version = ?", expectedVersion)
+	}
+	res := q.Updates(map[string]any{
+		"status":    "MERGED",
+		"merged_at": gorm.Expr("NOW()"),
+		"version":   gorm.Expr("version + 1"),
+	})
 
 	if res.Error != nil {
-		return pr.PullRequest{}, fmt.Errorf("%s: %w", op, res.Error)
+		return pr.PullRequest{}, false, fmt.Errorf("%s: %w", op, res.Error)
 	}
 
 	if res.RowsAffected == 0 {
-		var prGorm pgdto.PullRequest
-		err := p.db.Preload("AssignedReviewers").
-			Where("pull_request_id = ?", id).
-			First(&prGorm).Error
-
+		prGorm, err := findPullRequest(db, org, id)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return pr.PullRequest{}, ErrNotFound
+				return pr.PullRequest{}, false, ErrNotFound
 			}
-			return pr.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+			return pr.PullRequest{}, false, fmt.Errorf("%s: %w", op, err)
 		}
 
+		if expectedVersion != 0 && prGorm.Version != expectedVersion {
+			return pr.PullRequest{}, false, ErrVersionMismatch
+		}
 		if prGorm.Status == "MERGED" {
-			return prGorm.ToDomain(), nil
+			return prGorm.ToDomain(), false, nil
 		}
 
-		return pr.PullRequest{}, fmt.Errorf("%s: pull request is %s, not OPEN", op, prGorm.Status)
+		return pr.PullRequest{}, false, fmt.Errorf("%s: pull request is %s, not OPEN", op, prGorm.Status)
 	}
 
-	var prGorm pgdto.PullRequest
-	if err := p.db.Preload("AssignedReviewers").
-		Where("pull_request_id = ?", id).
-		First(&prGorm).Error; err != nil {
-
-		return pr.PullRequest{}, fmt.Errorf("%s: reload after merge: %w", op, err)
+	prGorm, err := findPullRequest(db, org, id)
+	if err != nil {
+		return pr.PullRequest{}, false, fmt.Errorf("%s: reload after merge: %w", op, err)
 	}
 
-	return prGorm.ToDomain(), nil
+	return prGorm.ToDomain(), true, nil
 }
 
-func (p *PostgresStorage) PullRequestReassign(r pr.PostPullRequestReassign) (pr.PullRequest, error) {
+func (p *PostgresStorage) PullRequestReassign(ctx context.Context, r pr.PostPullRequestReassign) (pr.PullRequest, error) {
 	const op = "storage.postgres.PullRequestReassign"
 
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
 	var prGorm pgdto.PullRequest
 
-	err := p.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Preload("AssignedReviewers").
-			First(&prGorm, "pull_request_id = ?", r.PullRequestId).Error; err != nil {
+	// REPEATABLE READ: список ревьюверов и кандидаты читаются из одного снимка;
+	// если PR успел измениться после начала транзакции, SELECT ... FOR UPDATE
+	// завершится ошибкой сериализации и транзакция будет повторена.
+	txOpts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead}
+	err := p.transaction(ctx, txOpts, func(tx *gorm.DB) error {
+		// строка PR блокируется до конца транзакции: переназначения одного PR
+		// выполняются строго по очереди
+		var locked pgdto.PullRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("org_id = ? AND pull_request_id = ?", org, r.PullRequestId).
+			First(&locked).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return ErrNotFound
 			}
 			return err
 		}
 
-		if prGorm.Status == "MERGED" {
+		if r.ExpectedVersion != 0 && locked.Version != r.ExpectedVersion {
+			return ErrVersionMismatch
+		}
+		if locked.Status == "MERGED" {
 			return ErrAlreadyMerged
 		}
-
-		found := false
-		for _, rev := range prGorm.AssignedReviewers {
-			if rev.UserID == r.OldUserId {
-				found = true
-				break
-			}
+		if err := attachReviewers(tx, org, []*pgdto.PullRequest{&locked}); err != nil {
+			return err
 		}
-		if !found {
+		if !slices.Contains(locked.AssignedReviewers, r.OldUserId) {
 			return ErrReviewerNotInPR
 		}
 
-		var author pgdto.User
-		if err := tx.Select("team_name").
-			First(&author, "user_id = ?", prGorm.AuthorID).Error; err != nil {
-			return err
+		// замену ищут в команде PR; у PR, созданных до появления членства
+		// в нескольких командах, её может не быть — берётся команда автора
+		teamName := ""
+		if locked.TeamName != nil {
+			teamName = *locked.TeamName
+		} else {
+			var author pgdto.User
+			if err := tx.Select("team_name").
+				First(&author, "org_id = ? AND user_id = ?", org, locked.AuthorID).Error; err != nil {
+				return err
+			}
+			teamName = author.TeamName
 		}
 
-		if author.TeamName == "" {
+		if teamName == "" {
 			return ErrNotAssigned
 		}
 
-		var candidate struct{ UserID string }
-		err := tx.Raw(`
-            SELECT user_id
-            FROM users
-            WHERE team_name = ?
-              AND is_active = true
-              AND user_id != ?
-              AND user_id != ?
-              AND user_id NOT IN (
-                SELECT user_id FROM pull_request_reviewers WHERE pull_request_id = ?
+		// если в команде PR замены нет, она ищется в родительских командах;
+		// занятые кандидаты идут последними и нужны, только чтобы отличить
+		// «все заняты» от «некого назначить»
+		var candidate struct {
+			UserID     string
+			AtCapacity bool
+		}
+		err := tx.Raw(chainCTE+`
+            SELECT u.user_id, `+atCapacitySQL+` AS at_capacity
+            FROM users u
+            JOIN team_memberships m ON m.org_id = u.org_id AND m.user_id = u.user_id
+            JOIN chain c ON c.team_name = m.team_name
+            JOIN teams t ON t.org_id = m.org_id AND t.team_name = m.team_name
+            WHERE u.org_id = ?
+              AND u.is_active = true
+              AND m.is_active = true
+              AND u.user_id != ?
+              AND u.user_id != ?
+              AND u.user_id NOT IN (
+                SELECT user_id FROM pull_request_reviewers
+                WHERE org_id = ? AND pull_request_id = ?
               )
+            ORDER BY at_capacity, c.depth, u.user_id
             LIMIT 1
-        `, author.TeamName, prGorm.AuthorID, r.OldUserId, r.PullRequestId).
+        `, org, teamName, org, maxTeamDepth,
+			org, locked.AuthorID, r.OldUserId, org, r.PullRequestId).
 			Scan(&candidate).Error
 
 		if err != nil {
@@ -275,24 +560,44 @@ func (p *PostgresStorage) PullRequestReassign(r pr.PostPullRequestReassign) (pr.
 		if candidate.UserID == "" {
 			return ErrNoCandidate
 		}
+		if candidate.AtCapacity {
+			return ErrAllAtCapacity
+		}
 
-		if err := tx.Exec(`
+		res := tx.Exec(`
             DELETE FROM pull_request_reviewers
-            WHERE pull_request_id = ? AND user_id = ?
-        `, r.PullRequestId, r.OldUserId).Error; err != nil {
-			return err
+            WHERE org_id = ? AND pull_request_id = ? AND user_id = ?
+        `, org, r.PullRequestId, r.OldUserId)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return ErrReviewerNotInPR
 		}
 
+		// без ON CONFLICT: под блокировкой дубликат невозможен, и если он всё же
+		// появится, транзакция должна упасть, а не молча потерять ревьювера
 		if err := tx.Exec(`
-            INSERT INTO pull_request_reviewers (pull_request_id, user_id)
-            VALUES (?, ?)
-            ON CONFLICT (pull_request_id, user_id) DO NOTHING
-        `, r.PullRequestId, candidate.UserID).Error; err != nil {
+            INSERT INTO pull_request_reviewers (org_id, pull_request_id, user_id)
+            VALUES (?, ?, ?)
+        `, org, r.PullRequestId, candidate.UserID).Error; err != nil {
+			return err
+		}
+		if err := recordPairings(tx, org, r.PullRequestId, locked.AuthorID, []string{candidate.UserID}); err != nil {
+			return err
+		}
+		if err := recordReviewerChange(tx, org, r.PullRequestId, pr.HistoryReassigned, r.OldUserId, candidate.UserID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&pgdto.PullRequest{}).
+			Where("org_id = ? AND pull_request_id = ?", org, r.PullRequestId).
+			Update("version", gorm.Expr("version + 1")).Error; err != nil {
 			return err
 		}
 
-		return tx.Preload("AssignedReviewers").
-			First(&prGorm, "pull_request_id = ?", r.PullRequestId).Error
+		prGorm, err = findPullRequest(tx, org, r.PullRequestId)
+		return err
 	})
 
 	if err != nil {
@@ -302,7 +607,14 @@ func (p *PostgresStorage) PullRequestReassign(r pr.PostPullRequestReassign) (pr.
 	return prGorm.ToDomain(), nil
 }
 
-func (p *PostgresStorage) TeamAdd(t pr.Team) (pr.Team, error) {
+func (p *PostgresStorage) TeamAdd(ctx context.Context, t pr.Team) (pr.Team, error) {
+	const op = "storage.postgres.TeamAdd"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+	org := auth.OrgID(ctx)
+
 	if t.TeamName == "" {
 		return pr.Team{}, fmt.Errorf("team name required")
 	}
@@ -310,7 +622,7 @@ func (p *PostgresStorage) TeamAdd(t pr.Team) (pr.Team, error) {
 		return pr.Team{}, ErrNoCandidate
 	}
 
-	tx := p.db.Begin()
+	tx := db.Begin()
 	if tx.Error != nil {
 		return pr.Team{}, tx.Error
 	}
@@ -318,7 +630,7 @@ func (p *PostgresStorage) TeamAdd(t pr.Team) (pr.Team, error) {
 
 	var exists int64
 	if err := tx.Model(&pgdto.TeamModel{}).
-		Where("team_name = ?", t.TeamName).
+		Where("org_id = ? AND team_name = ?", org, t.TeamName).
 		Count(&exists).Error; err != nil {
 		return pr.Team{}, err
 	}
@@ -326,7 +638,25 @@ func (p *PostgresStorage) TeamAdd(t pr.Team) (pr.Team, error) {
 		return pr.Team{}, ErrTeamExists
 	}
 
-	if err := tx.Create(&pgdto.TeamModel{TeamName: t.TeamName}).Error; err != nil {
+	if t.ParentTeam != "" {
+		if err := tx.Model(&pgdto.TeamModel{}).
+			Where("org_id = ? AND team_name = ?", org, t.ParentTeam).
+			Count(&exists).Error; err != nil {
+			return pr.Team{}, err
+		}
+		if exists == 0 {
+			return pr.Team{}, ErrNotFound
+		}
+	}
+
+	if err := tx.Create(&pgdto.TeamModel{
+		OrgID:              org,
+		TeamName:           t.TeamName,
+		ParentTeam:         nullIfEmpty(t.ParentTeam),
+		SelectionMode:      string(t.SelectionMode),
+		MaxOpenReviews:     nullIfZero(t.MaxOpenReviews),
+		PreferWorkingHours: t.PreferWorkingHours,
+	}).Error; err != nil {
 		return pr.Team{}, err
 	}
 
@@ -335,13 +665,15 @@ func (p *PostgresStorage) TeamAdd(t pr.Team) (pr.Team, error) {
 			return pr.Team{}, ErrNotFound
 		}
 
+		// пользователь остаётся в прежних командах; новая становится
+		// основной, только если основной ещё нет
 		result := tx.Exec(`
 			UPDATE users 
-			SET team_name = ?, 
+			SET team_name = COALESCE(team_name, ?), 
 			    is_active = ?, 
 			    username = ?
-			WHERE user_id = ?
-		`, t.TeamName, m.IsActive, m.Username, m.UserId)
+			WHERE org_id = ? AND user_id = ? AND anonymized_at IS NULL
+		`, t.TeamName, m.IsActive, m.Username, org, m.UserId)
 
 		if result.Error != nil {
 			return pr.Team{}, result.Error
@@ -349,81 +681,70 @@ func (p *PostgresStorage) TeamAdd(t pr.Team) (pr.Team, error) {
 
 		if result.RowsAffected == 0 {
 			if err := tx.Create(&pgdto.UserModel{
+				OrgID:    org,
 				UserID:   m.UserId,
 				Username: m.Username,
 				IsActive: m.IsActive,
 				TeamName: &t.TeamName,
 			}).Error; err != nil {
+				// строка есть, но не обновилась — пользователь анонимизирован
+				if isUniqueViolation(err) {
+					return pr.Team{}, ErrUserAnonymized
+				}
 				return pr.Team{}, fmt.Errorf("failed to create user %s: %w", m.UserId, err)
 			}
 		}
-	}
 
-	if err := tx.Commit().Error; err != nil {
-		return pr.Team{}, err
+		if err := addMembership(tx, org, t.TeamName, m); err != nil {
+			return pr.Team{}, err
+		}
 	}
 
-	var members []pr.TeamMember
-	if err := p.db.
-		Table("users").
-		Where("team_name = ?", t.TeamName).
-		Select("user_id", "username", "is_active").
-		Scan(&members).Error; err != nil {
+	if err := tx.Commit().Error; err != nil {
 		return pr.Team{}, err
 	}
 
-	return pr.Team{
-		TeamName: t.TeamName,
-		Members:  members,
-	}, nil
+	return loadTeam(db, org, t.TeamName)
 }
 
-func (p *PostgresStorage) TeamGet(teamName string) (pr.Team, error) {
+func (p *PostgresStorage) TeamGet(ctx context.Context, teamName string) (pr.Team, error) {
+	const op = "storage.postgres.TeamGet"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+
 	if teamName == "" {
 		return pr.Team{}, fmt.Errorf("team name is required")
 	}
 
-	var userModels []pgdto.UserModel
-
-	err := p.db.
-		Table("users").
-		Where("team_name = ?", teamName).
-		Find(&userModels).Error
-
+	team, err := loadTeam(db, auth.OrgID(ctx), teamName)
 	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return pr.Team{}, ErrNotFound
+		}
 		return pr.Team{}, fmt.Errorf("postgres.TeamGet: query failed: %w", err)
 	}
+	return team, nil
+}
 
-	if len(userModels) == 0 {
-		return pr.Team{}, ErrNotFound
-	}
-
-	members := make([]pr.TeamMember, 0, len(userModels))
-	for _, u := range userModels {
-		members = append(members, pr.TeamMember{
-			UserId:   u.UserID,
-			Username: u.Username,
-			IsActive: u.IsActive,
-		})
-	}
+func (p *PostgresStorage) UsersGetReview(ctx context.Context, userID string) ([]pr.PullRequest, error) {
+	const op = "storage.postgres.UsersGetReview"
 
-	return pr.Team{
-		TeamName: teamName,
-		Members:  members,
-	}, nil
-}
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	db := p.db.WithContext(ctx)
+	org := auth.OrgID(ctx)
 
-func (p *PostgresStorage) UsersGetReview(userID string) ([]pr.PullRequest, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("user_id is required")
 	}
 
 	var prModels []pgdto.PullRequest
 
-	err := p.db.
-		Joins("JOIN pull_request_reviewers prr ON prr.pull_request_id = pull_requests.pull_request_id").
-		Where("prr.user_id = ?", userID).
-		Preload("AssignedReviewers").
+	err := db.
+		Joins("JOIN pull_request_reviewers prr ON prr.org_id = pull_requests.org_id AND prr.pull_request_id = pull_requests.pull_request_id").
+		Where("pull_requests.org_id = ? AND prr.user_id = ?", org, userID).
 		Find(&prModels).Error
 
 	if err != nil {
@@ -434,9 +755,36 @@ func (p *PostgresStorage) UsersGetReview(userID string) ([]pr.PullRequest, error
 		return []pr.PullRequest{}, nil
 	}
 
+	ptrs := make([]*pgdto.PullRequest, len(prModels))
+	for i := range prModels {
+		ptrs[i] = &prModels[i]
+	}
+	if err := attachReviewers(db, org, ptrs); err != nil {
+		return nil, fmt.Errorf("postgres.UsersGetReview: load reviewers: %w", err)
+	}
+
+	// момент назначения — начало отсчёта срока ревью
+	var assigned []struct {
+		PullRequestID string
+		AssignedAt    time.Time
+	}
+	if err := db.Model(&pgdto.PullRequestReviewer{}).
+		Select("pull_request_id", "assigned_at").
+		Where("org_id = ? AND user_id = ?", org, userID).
+		Scan(&assigned).Error; err != nil {
+		return nil, fmt.Errorf("postgres.UsersGetReview: load assignments: %w", err)
+	}
+	assignedAt := make(map[string]time.Time, len(assigned))
+	for _, a := range assigned {
+		assignedAt[a.PullRequestID] = a.AssignedAt
+	}
+
 	result := make([]pr.PullRequest, len(prModels))
 	for i, model := range prModels {
 		result[i] = model.ToDomain()
+		if at, ok := assignedAt[model.PullRequestID]; ok {
+			result[i].ReviewAssignedAt = &at
+		}
 	}
 
 	return result, nil
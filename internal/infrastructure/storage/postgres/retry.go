@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	maxTxAttempts  = 4
+	retryBaseDelay = 5 * time.Millisecond
+)
+
+const (
+	pqSerializationFailure = "40001"
+	pqDeadlockDetected     = "40P01"
+	pqUniqueViolation      = "23505"
+)
+
+// isRetryable — ошибки, после которых транзакцию можно безопасно повторить целиком.
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == pqSerializationFailure || pqErr.Code == pqDeadlockDetected
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation
+}
+
+// transaction выполняет fn в транзакции и повторяет её при конфликте
+// сериализации или взаимной блокировке: не больше maxTxAttempts попыток с
+// экспоненциальной задержкой и джиттером. fn должна быть готова к повторному
+// вызову — всё состояние, которое она заполняет, перезаписывается заново.
+func (p *PostgresStorage) transaction(ctx context.Context, opts *sql.TxOptions, fn func(tx *gorm.DB) error) error {
+	span := trace.SpanFromContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		err := p.db.WithContext(ctx).Transaction(fn, opts)
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+		if attempt == maxTxAttempts {
+			return fmt.Errorf("%w: %w", ErrConcurrentUpdate, err)
+		}
+
+		span.AddEvent("transaction retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+		delay := retryBaseDelay<<(attempt-1) + rand.N(retryBaseDelay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
@@ -1,41 +1,50 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"pr-service/internal/domain/auth"
 
 
 	"github.com/brianvoe/gofakeit/v7"
 )
 
-func SeedUsersWithTeams(db *sql.DB) error {
+// SeedUsersWithTeams заполняет тестовыми данными организацию principal-а из ctx.
+func SeedUsersWithTeams(ctx context.Context, db *sql.DB) error {
 	gofakeit.Seed(12345)
+	orgID := auth.OrgID(ctx)
 
 	teams := []string{"Alpha", "Beta", "Gamma"}
 	for _, t := range teams {
-		db.Exec(`INSERT INTO teams (team_name) VALUES ($1) ON CONFLICT DO NOTHING`, t)
+		db.ExecContext(ctx, `INSERT INTO teams (org_id, team_name) VALUES ($1, $2) ON CONFLICT DO NOTHING`, orgID, t)
 	}
 
 	for i := 0; i < 10; i++ {
-		db.Exec(`
-			INSERT INTO users (user_id, username, team_name, is_active)
-			VALUES ($1, $2, $3, true)
-		`, gofakeit.UUID(), gofakeit.Username(), teams[i%3])
+		db.ExecContext(ctx, `
+			INSERT INTO users (org_id, user_id, username, team_name, is_active)
+			VALUES ($1, $2, $3, $4, true)
+		`, orgID, gofakeit.UUID(), gofakeit.Username(), teams[i%3])
 	}
 
 	for i := 0; i < 5; i++ {
-		db.Exec(`
-			INSERT INTO users (user_id, username, team_name, is_active)
-			VALUES ($1, $2, NULL, false)
-		`, gofakeit.UUID(), gofakeit.Username())
+		db.ExecContext(ctx, `
+			INSERT INTO users (org_id, user_id, username, team_name, is_active)
+			VALUES ($1, $2, $3, NULL, false)
+		`, orgID, gofakeit.UUID(), gofakeit.Username())
 	}
 
 	for i := 0; i < 3; i++ {
-		db.Exec(`
-			INSERT INTO users (user_id, username, team_name, is_active)
-			VALUES ($1, $2, NULL, true)
-		`, gofakeit.UUID(), gofakeit.Username())
+		db.ExecContext(ctx, `
+			INSERT INTO users (org_id, user_id, username, team_name, is_active)
+			VALUES ($1, $2, $3, NULL, true)
+		`, orgID, gofakeit.UUID(), gofakeit.Username())
 	}
 
+	db.ExecContext(ctx, `
+		INSERT INTO team_memberships (org_id, team_name, user_id)
+		SELECT org_id, team_name, user_id FROM users WHERE org_id = $1 AND team_name IS NOT NULL
+		ON CONFLICT DO NOTHING
+	`, orgID)
 
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,478 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+func (p *PostgresStorage) TeamAddMembers(ctx context.Context, r pr.TeamAddMembers) (pr.TeamChangeResult, error) {
+	const op = "storage.postgres.TeamAddMembers"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	if len(r.Members) == 0 {
+		return pr.TeamChangeResult{}, ErrNoCandidate
+	}
+
+	var res pr.TeamChangeResult
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		if err := lockTeam(tx, org, r.TeamName); err != nil {
+			return err
+		}
+
+		// добавление в команду не исключает из других команд,
+		// поэтому открытые ревью участников не затрагиваются
+		for _, m := range r.Members {
+			var prev pgdto.UserModel
+			found := tx.Where("org_id = ? AND user_id = ?", org, m.UserId).
+				Limit(1).Find(&prev)
+			if found.Error != nil {
+				return found.Error
+			}
+
+			if found.RowsAffected == 0 {
+				if err := tx.Create(&pgdto.UserModel{
+					OrgID:    org,
+					UserID:   m.UserId,
+					Username: m.Username,
+					IsActive: m.IsActive,
+					TeamName: &r.TeamName,
+				}).Error; err != nil {
+					return fmt.Errorf("failed to create user %s: %w", m.UserId, err)
+				}
+			} else {
+				if prev.AnonymizedAt != nil {
+					return ErrUserAnonymized
+				}
+				if err := tx.Exec(`
+					UPDATE users
+					SET team_name = COALESCE(team_name, ?), is_active = ?, username = ?
+					WHERE org_id = ? AND user_id = ?
+				`, r.TeamName, m.IsActive, m.Username, org, m.UserId).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := addMembership(tx, org, r.TeamName, m); err != nil {
+				return err
+			}
+		}
+
+		team, err := loadTeam(tx, org, r.TeamName)
+		if err != nil {
+			return err
+		}
+
+		res = pr.TeamChangeResult{
+			Team:          team,
+			Reassignments: []pr.Reassignment{},
+		}
+		return nil
+	})
+	if err != nil {
+		return pr.TeamChangeResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+func (p *PostgresStorage) TeamSetMemberActive(ctx context.Context, r pr.TeamSetMemberActive) (pr.Team, error) {
+	const op = "storage.postgres.TeamSetMemberActive"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var team pr.Team
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		upd := tx.Model(&pgdto.TeamMembershipModel{}).
+			Where("org_id = ? AND team_name = ? AND user_id = ?", org, r.TeamName, r.UserId).
+			Update("is_active", r.IsActive)
+		if upd.Error != nil {
+			return upd.Error
+		}
+		if upd.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		var err error
+		team, err = loadTeam(tx, org, r.TeamName)
+		return err
+	})
+	if err != nil {
+		return pr.Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return team, nil
+}
+
+func (p *PostgresStorage) TeamRemoveMembers(ctx context.Context, r pr.TeamRemoveMembers) (pr.TeamChangeResult, error) {
+	const op = "storage.postgres.TeamRemoveMembers"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	userIDs := slices.Compact(slices.Sorted(slices.Values(r.UserIds)))
+
+	var res pr.TeamChangeResult
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		if err := lockTeam(tx, org, r.TeamName); err != nil {
+			return err
+		}
+
+		del := tx.Exec(`
+			DELETE FROM team_memberships
+			WHERE org_id = ? AND team_name = ? AND user_id IN ?
+		`, org, r.TeamName, userIDs)
+		if del.Error != nil {
+			return del.Error
+		}
+		// хотя бы один пользователь не состоит в команде — изменения откатываются
+		if del.RowsAffected != int64(len(userIDs)) {
+			return ErrNotFound
+		}
+		if err := resetPrimaryTeam(tx, org, r.TeamName, userIDs); err != nil {
+			return err
+		}
+
+		reassignments, err := releaseOpenReviews(tx, org, userIDs, r.OpenReviews)
+		if err != nil {
+			return err
+		}
+		team, err := loadTeam(tx, org, r.TeamName)
+		if err != nil {
+			return err
+		}
+
+		res = pr.TeamChangeResult{
+			Team:          team,
+			Reassignments: reassignments,
+		}
+		return nil
+	})
+	if err != nil {
+		return pr.TeamChangeResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+func (p *PostgresStorage) TeamRename(ctx context.Context, r pr.TeamRename) (pr.Team, error) {
+	const op = "storage.postgres.TeamRename"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var team pr.Team
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		if err := lockTeam(tx, org, r.TeamName); err != nil {
+			return err
+		}
+
+		var exists int64
+		if err := tx.Model(&pgdto.TeamModel{}).
+			Where("org_id = ? AND team_name = ?", org, r.NewTeamName).
+			Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists > 0 {
+			return ErrTeamExists
+		}
+
+		// users, team_memberships и api_keys обновляются внешними ключами ON UPDATE CASCADE
+		if err := tx.Model(&pgdto.TeamModel{}).
+			Where("org_id = ? AND team_name = ?", org, r.TeamName).
+			Update("team_name", r.NewTeamName).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrTeamExists
+			}
+			return err
+		}
+		// у pull_requests.team_name внешнего ключа нет: после удаления команды
+		// PR сохраняют её имя
+		if err := tx.Model(&pgdto.PullRequest{}).
+			Where("org_id = ? AND team_name = ?", org, r.TeamName).
+			Update("team_name", r.NewTeamName).Error; err != nil {
+			return err
+		}
+		// команды-владельцы в CODEOWNERS других команд хранятся по имени
+		if err := tx.Exec(`
+			UPDATE team_codeowners
+			SET owner_teams = array_replace(owner_teams, ?, ?)
+			WHERE org_id = ? AND ? = ANY(owner_teams)
+		`, r.TeamName, r.NewTeamName, org, r.TeamName).Error; err != nil {
+			return err
+		}
+
+		var err error
+		team, err = loadTeam(tx, org, r.NewTeamName)
+		return err
+	})
+	if err != nil {
+		return pr.Team{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return team, nil
+}
+
+func (p *PostgresStorage) TeamDelete(ctx context.Context, r pr.TeamDelete) ([]pr.Reassignment, error) {
+	const op = "storage.postgres.TeamDelete"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var reassignments []pr.Reassignment
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		// иерархия блокируется раньше команды — в том же порядке, что и в TeamSetParent
+		if err := lockTeamTree(tx, org); err != nil {
+			return err
+		}
+		if err := lockTeam(tx, org, r.TeamName); err != nil {
+			return err
+		}
+
+		var userIDs []string
+		if err := tx.Model(&pgdto.TeamMembershipModel{}).
+			Where("org_id = ? AND team_name = ?", org, r.TeamName).
+			Order("user_id").
+			Pluck("user_id", &userIDs).Error; err != nil {
+			return err
+		}
+
+		// подкоманды переходят к родителю удаляемой команды
+		if err := tx.Exec(`
+			UPDATE teams
+			SET parent_team = (
+			    SELECT parent_team FROM teams WHERE org_id = ? AND team_name = ?
+			)
+			WHERE org_id = ? AND parent_team = ?
+		`, org, r.TeamName, org, r.TeamName).Error; err != nil {
+			return err
+		}
+
+		// членство удаляется до releaseOpenReviews, иначе ревью участников
+		// ещё считались бы ревью своей команды
+		if err := tx.Exec(`
+			DELETE FROM team_memberships
+			WHERE org_id = ? AND team_name = ?
+		`, org, r.TeamName).Error; err != nil {
+			return err
+		}
+		if err := resetPrimaryTeam(tx, org, r.TeamName, userIDs); err != nil {
+			return err
+		}
+
+		// ключ с командой без неё получил бы права на все PR организации,
+		// поэтому ключи команды отзываются
+		if err := tx.Exec(`
+			UPDATE api_keys
+			SET revoked_at = COALESCE(revoked_at, NOW()), team_name = NULL
+			WHERE org_id = ? AND team_name = ?
+		`, org, r.TeamName).Error; err != nil {
+			return err
+		}
+
+		var err error
+		reassignments, err = releaseOpenReviews(tx, org, userIDs, r.OpenReviews)
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("org_id = ? AND team_name = ?", org, r.TeamName).
+			Delete(&pgdto.TeamModel{}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return reassignments, nil
+}
+
+// lockTeam блокирует строку команды до конца транзакции, чтобы изменения
+// состава одной команды не выполнялись параллельно.
+func lockTeam(tx *gorm.DB, org, teamName string) error {
+	var locked []pgdto.TeamModel
+	if err := tx.Raw(`
+		SELECT org_id, team_name FROM teams
+		WHERE org_id = ? AND team_name = ?
+		FOR UPDATE
+	`, org, teamName).Scan(&locked).Error; err != nil {
+		return err
+	}
+	if len(locked) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// teamMembers возвращает участников команды. IsActive — участвует ли
+// пользователь в ревью команды: активен и он сам, и его членство.
+func teamMembers(tx *gorm.DB, org, teamName string) ([]pr.TeamMember, error) {
+	var rows []struct {
+		UserID   string
+		Username string
+		IsActive bool
+		Role     string
+	}
+	if err := tx.Raw(`
+		SELECT u.user_id, u.username, u.is_active AND m.is_active AS is_active, m.role
+		FROM team_memberships m
+		JOIN users u ON u.org_id = m.org_id AND u.user_id = m.user_id
+		WHERE m.org_id = ? AND m.team_name = ?
+		ORDER BY u.user_id
+	`, org, teamName).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	members := make([]pr.TeamMember, 0, len(rows))
+	for _, u := range rows {
+		members = append(members, pr.TeamMember{
+			UserId:   u.UserID,
+			Username: u.Username,
+			IsActive: u.IsActive,
+			Role:     pr.MembershipRole(u.Role),
+		})
+	}
+	if err := attachLeadRules(tx, org, teamName, members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// addMembership добавляет пользователя в команду. Роль существующего
+// членства меняется, только если она указана.
+func addMembership(tx *gorm.DB, org, teamName string, m pr.TeamMember) error {
+	return tx.Exec(`
+		INSERT INTO team_memberships (org_id, team_name, user_id, role)
+		VALUES (?, ?, ?, COALESCE(NULLIF(?, ''), 'member'))
+		ON CONFLICT (org_id, team_name, user_id)
+		DO UPDATE SET role = COALESCE(NULLIF(?, ''), team_memberships.role)
+	`, org, teamName, m.UserId, string(m.Role), string(m.Role)).Error
+}
+
+// resetPrimaryTeam назначает пользователям, у которых основной была команда
+// teamName, новую основную — первую по имени из оставшихся (или никакую).
+func resetPrimaryTeam(tx *gorm.DB, org, teamName string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return tx.Exec(`
+		UPDATE users u
+		SET team_name = (
+		    SELECT m.team_name FROM team_memberships m
+		    WHERE m.org_id = u.org_id AND m.user_id = u.user_id AND m.team_name <> ?
+		    ORDER BY m.team_name
+		    LIMIT 1
+		)
+		WHERE u.org_id = ? AND u.team_name = ? AND u.user_id IN ?
+	`, teamName, org, teamName, userIDs).Error
+}
+
+// releaseOpenReviews обрабатывает открытые ревью пользователей userIDs, которые
+// после изменения состава больше не состоят в команде PR или были
+// анонимизированы. При
+// OpenReviewsReassign ревьювер заменяется наименее загруженным активным
+// участником команды PR (или ближайшей родительской команды, если в ней
+// никого нет), а если замены нет — снимается с PR.
+func releaseOpenReviews(tx *gorm.DB, org string, userIDs []string, policy pr.OpenReviews) ([]pr.Reassignment, error) {
+	if policy != pr.OpenReviewsReassign || len(userIDs) == 0 {
+		return []pr.Reassignment{}, nil
+	}
+
+	var affected []struct {
+		PullRequestID string
+		UserID        string
+		AuthorID      string
+		TeamName      *string
+	}
+	// строки PR блокируются в порядке pull_request_id, как и при обычном переназначении
+	if err := tx.Raw(`
+		SELECT prr.pull_request_id, prr.user_id, p.author_id,
+		       COALESCE(p.team_name, a.team_name) AS team_name
+		FROM pull_request_reviewers prr
+		JOIN pull_requests p ON p.org_id = prr.org_id AND p.pull_request_id = prr.pull_request_id
+		JOIN users a ON a.org_id = p.org_id AND a.user_id = p.author_id
+		JOIN users rv ON rv.org_id = prr.org_id AND rv.user_id = prr.user_id
+		WHERE prr.org_id = ?
+		  AND prr.user_id IN ?
+		  AND p.status = 'OPEN'
+		  AND (rv.anonymized_at IS NOT NULL OR NOT EXISTS (
+		    SELECT 1 FROM team_memberships m
+		    WHERE m.org_id = prr.org_id
+		      AND m.user_id = prr.user_id
+		      AND m.team_name = COALESCE(p.team_name, a.team_name)
+		  ))
+		ORDER BY prr.pull_request_id, prr.user_id
+		FOR UPDATE OF p
+	`, org, userIDs).Scan(&affected).Error; err != nil {
+		return nil, err
+	}
+
+	reassignments := make([]pr.Reassignment, 0, len(affected))
+	for _, row := range affected {
+		// замена ищется в команде PR, а если там никого нет — выше по иерархии
+		var candidate struct{ UserID string }
+		if row.TeamName != nil {
+			if err := tx.Raw(chainCTE+`
+				SELECT u.user_id
+				FROM users u
+				JOIN team_memberships m ON m.org_id = u.org_id AND m.user_id = u.user_id
+				JOIN chain c ON c.team_name = m.team_name
+				JOIN teams t ON t.org_id = m.org_id AND t.team_name = m.team_name
+				WHERE u.org_id = ?
+				  AND u.is_active = true
+				  AND m.is_active = true
+				  AND NOT `+atCapacitySQL+`
+				  AND u.user_id != ?
+				  AND u.user_id NOT IN (
+				    SELECT user_id FROM pull_request_reviewers
+				    WHERE org_id = ? AND pull_request_id = ?
+				  )
+				ORDER BY c.depth, `+openReviewsSQL+`, u.user_id
+				LIMIT 1
+			`, org, *row.TeamName, org, maxTeamDepth,
+				org, row.AuthorID, org, row.PullRequestID).Scan(&candidate).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		if err := tx.Exec(`
+			DELETE FROM pull_request_reviewers
+			WHERE org_id = ? AND pull_request_id = ? AND user_id = ?
+		`, org, row.PullRequestID, row.UserID).Error; err != nil {
+			return nil, err
+		}
+		if candidate.UserID != "" {
+			if err := tx.Exec(`
+				INSERT INTO pull_request_reviewers (org_id, pull_request_id, user_id)
+				VALUES (?, ?, ?)
+			`, org, row.PullRequestID, candidate.UserID).Error; err != nil {
+				return nil, err
+			}
+			if err := recordPairings(tx, org, row.PullRequestID, row.AuthorID, []string{candidate.UserID}); err != nil {
+				return nil, err
+			}
+		}
+		if err := recordReviewerChange(tx, org, row.PullRequestID, pr.HistoryReleased, row.UserID, candidate.UserID); err != nil {
+			return nil, err
+		}
+		if err := tx.Model(&pgdto.PullRequest{}).
+			Where("org_id = ? AND pull_request_id = ?", org, row.PullRequestID).
+			Update("version", gorm.Expr("version + 1")).Error; err != nil {
+			return nil, err
+		}
+
+		reassignments = append(reassignments, pr.Reassignment{
+			PullRequestId: row.PullRequestID,
+			OldUserId:     row.UserID,
+			NewUserId:     candidate.UserID,
+		})
+	}
+	return reassignments, nil
+}
@@ -0,0 +1,425 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"pr-service/internal/domain/auth"
+	"pr-service/internal/domain/pr"
+	pgdto "pr-service/internal/infrastructure/storage/postgres/dto"
+
+	"gorm.io/gorm"
+)
+
+// anonymizedUsername — имя, которое получает пользователь после анонимизации.
+const anonymizedUsername = "anonymized"
+
+func (p *PostgresStorage) UserGet(ctx context.Context, id string) (pr.User, error) {
+	const op = "storage.postgres.UserGet"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	var models []pgdto.UserModel
+	if err := p.db.WithContext(ctx).
+		Where("org_id = ? AND user_id = ?", auth.OrgID(ctx), id).
+		Limit(1).
+		Find(&models).Error; err != nil {
+		return pr.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(models) == 0 {
+		return pr.User{}, ErrNotFound
+	}
+
+	users := []pr.User{models[0].ToDomain()}
+	if err := attachTeams(p.db.WithContext(ctx), auth.OrgID(ctx), users); err != nil {
+		return pr.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := attachSkills(p.db.WithContext(ctx), auth.OrgID(ctx), users); err != nil {
+		return pr.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return users[0], nil
+}
+
+func (p *PostgresStorage) UsersList(ctx context.Context, f pr.UsersListFilter) (pr.UsersPage, error) {
+	const op = "storage.postgres.UsersList"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	q := p.db.WithContext(ctx).Where("org_id = ?", auth.OrgID(ctx))
+	if f.TeamName != nil {
+		q = q.Where(`EXISTS (
+			SELECT 1 FROM team_memberships m
+			WHERE m.org_id = users.org_id AND m.user_id = users.user_id AND m.team_name = ?
+		)`, *f.TeamName)
+	}
+	if f.IsActive != nil {
+		q = q.Where("is_active = ?", *f.IsActive)
+	}
+	if f.After != "" {
+		q = q.Where("user_id > ?", f.After)
+	}
+
+	// лишняя строка показывает, что за страницей есть продолжение
+	var models []pgdto.UserModel
+	if err := q.Order("user_id").Limit(f.Limit + 1).Find(&models).Error; err != nil {
+		return pr.UsersPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var page pr.UsersPage
+	if len(models) > f.Limit {
+		models = models[:f.Limit]
+		page.NextAfter = models[len(models)-1].UserID
+	}
+	page.Users = make([]pr.User, 0, len(models))
+	for _, m := range models {
+		page.Users = append(page.Users, m.ToDomain())
+	}
+	if err := attachTeams(p.db.WithContext(ctx), auth.OrgID(ctx), page.Users); err != nil {
+		return pr.UsersPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := attachSkills(p.db.WithContext(ctx), auth.OrgID(ctx), page.Users); err != nil {
+		return pr.UsersPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return page, nil
+}
+
+func (p *PostgresStorage) UserUpdate(ctx context.Context, r pr.UserUpdate) (pr.UserChangeResult, error) {
+	const op = "storage.postgres.UserUpdate"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var res pr.UserChangeResult
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		user, err := lockUser(tx, org, r.UserId)
+		if err != nil {
+			return err
+		}
+		if user.AnonymizedAt != nil {
+			return ErrUserAnonymized
+		}
+
+		updates := map[string]any{}
+		if r.Username != nil {
+			updates["username"] = *r.Username
+		}
+		if r.Seniority != nil {
+			updates["seniority"] = nullIfEmpty(string(*r.Seniority))
+		}
+		if r.MaxOpenReviews != nil {
+			updates["max_open_reviews"] = nullIfZero(*r.MaxOpenReviews)
+		}
+
+		// смена основной команды переводит пользователя: членство в прежней
+		// основной команде заменяется членством в новой, остальные не меняются
+		moved := false
+		if r.TeamName != nil && *r.TeamName != user.TeamName {
+			if user.TeamName != "" {
+				if err := tx.Exec(`
+					DELETE FROM team_memberships
+					WHERE org_id = ? AND team_name = ? AND user_id = ?
+				`, org, user.TeamName, r.UserId).Error; err != nil {
+					return err
+				}
+			}
+			if *r.TeamName == "" {
+				if err := resetPrimaryTeam(tx, org, user.TeamName, []string{r.UserId}); err != nil {
+					return err
+				}
+			} else {
+				if err := lockTeam(tx, org, *r.TeamName); err != nil {
+					return err
+				}
+				if err := addMembership(tx, org, *r.TeamName, pr.TeamMember{UserId: r.UserId}); err != nil {
+					return err
+				}
+				updates["team_name"] = *r.TeamName
+			}
+			moved = true
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&pgdto.UserModel{}).
+				Where("org_id = ? AND user_id = ?", org, r.UserId).
+				Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		res.Reassignments = []pr.Reassignment{}
+		if moved {
+			if res.Reassignments, err = releaseOpenReviews(tx, org, []string{r.UserId}, r.OpenReviews); err != nil {
+				return err
+			}
+		}
+
+		res.User, err = lockUser(tx, org, r.UserId)
+		return err
+	})
+	if err != nil {
+		return pr.UserChangeResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+func (p *PostgresStorage) UserAnonymize(ctx context.Context, r pr.UserAnonymize) (pr.UserChangeResult, error) {
+	const op = "storage.postgres.UserAnonymize"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var res pr.UserChangeResult
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		user, err := lockUser(tx, org, r.UserId)
+		if err != nil {
+			return err
+		}
+		res.Reassignments = []pr.Reassignment{}
+		// повторная анонимизация ничего не меняет
+		if user.AnonymizedAt != nil {
+			res.User = user
+			return nil
+		}
+
+		if err := tx.Exec(`
+			UPDATE users
+			SET username = ?, is_active = false, team_name = NULL, anonymized_at = NOW(),
+			    timezone = NULL, work_start = NULL, work_end = NULL, work_days = NULL
+			WHERE org_id = ? AND user_id = ?
+		`, anonymizedUsername, org, r.UserId).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`
+			DELETE FROM team_memberships
+			WHERE org_id = ? AND user_id = ?
+		`, org, r.UserId).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("org_id = ? AND user_id = ?", org, r.UserId).
+			Delete(&pgdto.UserSkillModel{}).Error; err != nil {
+			return err
+		}
+
+		// ключи пользователя действовали бы от имени стёртой учётной записи
+		if err := tx.Exec(`
+			UPDATE api_keys
+			SET revoked_at = NOW()
+			WHERE org_id = ? AND subject = ? AND revoked_at IS NULL
+		`, org, r.UserId).Error; err != nil {
+			return err
+		}
+
+		// сохранённые ответы повторили бы имя и данные пользователя до анонимизации:
+		// удаляются ключи его запросов и ответы, где встречается его user_id
+		// как JSON-строка (так же его кодирует transport.WriteJSON)
+		quotedID, err := json.Marshal(r.UserId)
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec(`
+			DELETE FROM idempotency_keys
+			WHERE org_id = ? AND (subject = ? OR position(?::bytea IN response_body) > 0)
+		`, org, r.UserId, quotedID).Error; err != nil {
+			return err
+		}
+
+		if res.Reassignments, err = releaseOpenReviews(tx, org, []string{r.UserId}, r.OpenReviews); err != nil {
+			return err
+		}
+
+		res.User, err = lockUser(tx, org, r.UserId)
+		return err
+	})
+	if err != nil {
+		return pr.UserChangeResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+func (p *PostgresStorage) UserSetSkills(ctx context.Context, r pr.UserSetSkills) (pr.User, error) {
+	const op = "storage.postgres.UserSetSkills"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var user pr.User
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		locked, err := lockUser(tx, org, r.UserId)
+		if err != nil {
+			return err
+		}
+		if locked.AnonymizedAt != nil {
+			return ErrUserAnonymized
+		}
+
+		if err := tx.Where("org_id = ? AND user_id = ?", org, r.UserId).
+			Delete(&pgdto.UserSkillModel{}).Error; err != nil {
+			return err
+		}
+		if len(r.Skills) > 0 {
+			rows := make([]pgdto.UserSkillModel, 0, len(r.Skills))
+			for _, sk := range r.Skills {
+				rows = append(rows, pgdto.UserSkillModel{
+					OrgID:  org,
+					UserID: r.UserId,
+					Tag:    sk.Tag,
+					Level:  string(sk.Level),
+				})
+			}
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+
+		user, err = lockUser(tx, org, r.UserId)
+		return err
+	})
+	if err != nil {
+		return pr.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+func (p *PostgresStorage) UserSetWorkingHours(ctx context.Context, r pr.UserSetWorkingHours) (pr.User, error) {
+	const op = "storage.postgres.UserSetWorkingHours"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+	org := auth.OrgID(ctx)
+
+	var user pr.User
+	err := p.transaction(ctx, nil, func(tx *gorm.DB) error {
+		locked, err := lockUser(tx, org, r.UserId)
+		if err != nil {
+			return err
+		}
+		if locked.AnonymizedAt != nil {
+			return ErrUserAnonymized
+		}
+
+		cols := pgdto.WorkingHoursFromDomain(r.WorkingHours)
+		if err := tx.Model(&pgdto.UserModel{}).
+			Where("org_id = ? AND user_id = ?", org, r.UserId).
+			Updates(map[string]any{
+				"timezone":   cols.Timezone,
+				"work_start": cols.WorkStart,
+				"work_end":   cols.WorkEnd,
+				"work_days":  cols.WorkDays,
+			}).Error; err != nil {
+			return err
+		}
+
+		user, err = lockUser(tx, org, r.UserId)
+		return err
+	})
+	if err != nil {
+		return pr.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+func (p *PostgresStorage) UsersSkills(ctx context.Context, ids []string) (map[string][]pr.Skill, error) {
+	const op = "storage.postgres.UsersSkills"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	skills := make(map[string][]pr.Skill)
+	if len(ids) == 0 {
+		return skills, nil
+	}
+	var rows []pgdto.UserSkillModel
+	if err := p.db.WithContext(ctx).
+		Where("org_id = ? AND user_id IN ?", auth.OrgID(ctx), ids).
+		Order("user_id, tag").
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, row := range rows {
+		skills[row.UserID] = append(skills[row.UserID], row.ToDomain())
+	}
+	return skills, nil
+}
+
+// lockUser читает пользователя и блокирует его строку до конца транзакции.
+func lockUser(tx *gorm.DB, org, userID string) (pr.User, error) {
+	var locked []pgdto.UserModel
+	if err := tx.Raw(`
+		SELECT org_id, user_id, username, is_active, team_name, anonymized_at, seniority, max_open_reviews,
+		       timezone, work_start, work_end, work_days
+		FROM users
+		WHERE org_id = ? AND user_id = ?
+		FOR UPDATE
+	`, org, userID).Scan(&locked).Error; err != nil {
+		return pr.User{}, err
+	}
+	if len(locked) == 0 {
+		return pr.User{}, ErrNotFound
+	}
+
+	users := []pr.User{locked[0].ToDomain()}
+	if err := attachTeams(tx, org, users); err != nil {
+		return pr.User{}, err
+	}
+	if err := attachSkills(tx, org, users); err != nil {
+		return pr.User{}, err
+	}
+	return users[0], nil
+}
+
+// attachTeams одним запросом заполняет Teams у переданных пользователей.
+func attachTeams(tx *gorm.DB, org string, users []pr.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(users))
+	byID := make(map[string]*pr.User, len(users))
+	for i := range users {
+		users[i].Teams = []string{}
+		ids = append(ids, users[i].UserId)
+		byID[users[i].UserId] = &users[i]
+	}
+
+	var rows []pgdto.TeamMembershipModel
+	if err := tx.Where("org_id = ? AND user_id IN ?", org, ids).
+		Order("team_name").
+		Find(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		u := byID[row.UserID]
+		u.Teams = append(u.Teams, row.TeamName)
+	}
+	return nil
+}
+
+// attachSkills одним запросом заполняет Skills у переданных пользователей.
+func attachSkills(tx *gorm.DB, org string, users []pr.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(users))
+	byID := make(map[string]*pr.User, len(users))
+	for i := range users {
+		users[i].Skills = []pr.Skill{}
+		ids = append(ids, users[i].UserId)
+		byID[users[i].UserId] = &users[i]
+	}
+
+	var rows []pgdto.UserSkillModel
+	if err := tx.Where("org_id = ? AND user_id IN ?", org, ids).
+		Order("tag").
+		Find(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		u := byID[row.UserID]
+		u.Skills = append(u.Skills, row.ToDomain())
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Package lifecycle запускает и останавливает компоненты приложения по порядку.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pr-service/pkg/sl_logger/sl"
+)
+
+// Hook описывает один компонент. OnStart вызываются в порядке регистрации,
+// OnStop — в обратном, поэтому зарегистрированный первым компонент (например,
+// база) останавливается последним.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// WorkerState — состояние фонового воркера, зарегистрированного через Go.
+type WorkerState string
+
+const (
+	WorkerPending WorkerState = "pending"
+	WorkerRunning WorkerState = "running"
+	WorkerStopped WorkerState = "stopped"
+	WorkerFailed  WorkerState = "failed"
+)
+
+type Lifecycle struct {
+	log *slog.Logger
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+
+	stateMu  sync.RWMutex
+	workers  map[string]WorkerState
+	stopping atomic.Bool
+
+	errCh chan error
+}
+
+func New(log *slog.Logger) *Lifecycle {
+	return &Lifecycle{
+		log:     log.With(slog.String("component", "lifecycle")),
+		workers: make(map[string]WorkerState),
+		errCh:   make(chan error, 1),
+	}
+}
+
+// Append регистрирует хук. Хуки добавляются до Start.
+func (l *Lifecycle) Append(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, h)
+}
+
+// Go регистрирует фоновый воркер. Воркер запускается в своей горутине, его
+// контекст отменяется при остановке; Stop ждёт, пока run вернётся или истечёт
+// контекст остановки. Ошибка воркера до остановки останавливает всё
+// приложение.
+func (l *Lifecycle) Go(name string, run func(ctx context.Context) error) {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+
+	l.setWorkerState(name, WorkerPending)
+	l.Append(Hook{
+		Name: name,
+		OnStart: func(_ context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+
+			l.setWorkerState(name, WorkerRunning)
+			go func() {
+				defer close(done)
+				if err := run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+					l.setWorkerState(name, WorkerFailed)
+					l.Fail(fmt.Errorf("%s: %w", name, err))
+					return
+				}
+				l.setWorkerState(name, WorkerStopped)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return fmt.Errorf("%s: %w", name, ctx.Err())
+			}
+		},
+	})
+}
+
+// Workers возвращает снимок состояний фоновых воркеров.
+func (l *Lifecycle) Workers() map[string]WorkerState {
+	l.stateMu.RLock()
+	defer l.stateMu.RUnlock()
+
+	states := make(map[string]WorkerState, len(l.workers))
+	for name, state := range l.workers {
+		states[name] = state
+	}
+	return states
+}
+
+// Stopping сообщает, началась ли остановка.
+func (l *Lifecycle) Stopping() bool {
+	return l.stopping.Load()
+}
+
+func (l *Lifecycle) setWorkerState(name string, state WorkerState) {
+	l.stateMu.Lock()
+	defer l.stateMu.Unlock()
+	l.workers[name] = state
+}
+
+// Fail сообщает о фатальной ошибке компонента, после которой Run начинает
+// остановку. Сохраняется только первая ошибка.
+func (l *Lifecycle) Fail(err error) {
+	select {
+	case l.errCh <- err:
+	default:
+	}
+}
+
+// Start вызывает OnStart по порядку и прерывается на первой ошибке.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, h := range l.hooks[l.started:] {
+		if h.OnStart != nil {
+			l.log.Info("starting", slog.String("hook", h.Name))
+			if err := h.OnStart(ctx); err != nil {
+				return fmt.Errorf("lifecycle: start %s: %w", h.Name, err)
+			}
+		}
+		l.started++
+	}
+	return nil
+}
+
+// Stop вызывает OnStop запущенных компонентов в обратном порядке. Хуки
+// вызываются все, даже если какие-то из них падают; ошибки объединяются.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.stopping.Store(true)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+	for ; l.started > 0; l.started-- {
+		h := l.hooks[l.started-1]
+		if h.OnStop == nil {
+			continue
+		}
+
+		start := time.Now()
+		if err := h.OnStop(ctx); err != nil {
+			l.log.Error("failed to stop", slog.String("hook", h.Name), sl.Err(err))
+			errs = append(errs, fmt.Errorf("lifecycle: stop %s: %w", h.Name, err))
+			continue
+		}
+		l.log.Info("stopped",
+			slog.String("hook", h.Name),
+			slog.String("duration", time.Since(start).String()),
+		)
+	}
+	return errors.Join(errs...)
+}
+
+// Run запускает все компоненты, ждёт отмены ctx или сбоя компонента и затем
+// останавливает всё за stopTimeout.
+func (l *Lifecycle) Run(ctx context.Context, stopTimeout time.Duration) error {
+	runErr := l.Start(ctx)
+	if runErr == nil {
+		select {
+		case <-ctx.Done():
+			l.log.Info("shutdown signal received")
+		case runErr = <-l.errCh:
+			l.log.Error("component failed, shutting down", sl.Err(runErr))
+		}
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+
+	return errors.Join(runErr, l.Stop(stopCtx))
+}
@@ -0,0 +1,91 @@
+// Гонка переназначений: много VU одновременно переназначают ревьюверов одного PR.
+// Запуск: make race (сервис должен быть поднят, см. make up).
+//
+// Проверяется, что под нагрузкой:
+//   - сервер не отвечает 5xx;
+//   - у PR в итоге ровно 2 разных ревьювера, автор среди них не оказывается.
+import http from 'k6/http';
+import { check, fail } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+const TEAM_SIZE = 8;
+
+export const options = {
+  vus: 50,
+  iterations: 2000,
+  thresholds: {
+    checks: ['rate==1'],
+  },
+};
+
+const params = {
+  headers: {
+    'Content-Type': 'application/json',
+    'X-API-Key': __ENV.API_KEY,
+  },
+};
+
+export function setup() {
+  const suffix = `${Date.now()}`;
+  const members = [];
+  for (let i = 0; i < TEAM_SIZE; i++) {
+    members.push({ user_id: `race-${suffix}-u${i}`, username: `race-u${i}`, is_active: true });
+  }
+
+  const team = http.post(`${BASE_URL}/team/add`,
+    JSON.stringify({ team_name: `race-${suffix}`, members }), params);
+  if (team.status !== 200 && team.status !== 201) {
+    fail(`team/add: ${team.status} ${team.body}`);
+  }
+
+  const prId = `race-${suffix}-pr`;
+  const created = http.post(`${BASE_URL}/pullRequest/create`, JSON.stringify({
+    pull_request_id: prId,
+    pull_request_name: 'race',
+    author_id: members[0].user_id,
+  }), params);
+  if (created.status !== 200 && created.status !== 201) {
+    fail(`pullRequest/create: ${created.status} ${created.body}`);
+  }
+
+  return { prId, author: members[0].user_id, members: members.map((m) => m.user_id) };
+}
+
+export default function (data) {
+  // кандидат на замену выбирается случайно: часть запросов попадёт в
+  // неназначенного пользователя и должна получить 4xx, а не испортить PR
+  const reviewers = data.members.slice(1);
+  const oldUser = reviewers[Math.floor(Math.random() * reviewers.length)];
+
+  const res = http.post(`${BASE_URL}/pullRequest/reassign`,
+    JSON.stringify({ pull_request_id: data.prId, old_user_id: oldUser }), params);
+
+  check(res, {
+    'no 5xx': (r) => r.status < 500,
+    'reviewers stay consistent': (r) => {
+      if (r.status !== 200) {
+        return true;
+      }
+      const reviewersNow = r.json('assigned_reviewers');
+      return reviewersNow.length === 2 &&
+        new Set(reviewersNow).size === 2 &&
+        !reviewersNow.includes(data.author);
+    },
+  });
+}
+
+export function teardown(data) {
+  const assigned = [];
+  for (const userId of data.members) {
+    const res = http.get(`${BASE_URL}/users/getReview?user_id=${userId}`, params);
+    const prs = res.json() || [];
+    if (prs.some((p) => p.pull_request_id === data.prId)) {
+      assigned.push(userId);
+    }
+  }
+
+  check(assigned, {
+    'exactly 2 reviewers after race': (a) => a.length === 2,
+    'author is not a reviewer': (a) => !a.includes(data.author),
+  });
+}
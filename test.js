@@ -5,8 +5,13 @@ export const options = {
   duration: '10s',
 };
 
+const params = {
+  headers: { 'X-API-Key': __ENV.API_KEY },
+};
+
 export default function () {
   http.get(
-    'http://localhost:8080/team/get?team_name=Alpha'
+    'http://localhost:8080/team/get?team_name=Alpha',
+    params
   );
-}
\ No newline at end of file
+}